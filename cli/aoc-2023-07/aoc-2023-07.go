@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/denarced/advent-of-code/lib/aoc2307"
 	"github.com/denarced/advent-of-code/shared"
@@ -20,5 +21,19 @@ func main() {
 	fmt.Println("Total winnings:")
 	fmt.Printf("    Without jokers: %d\n", aoc2307.CountWinnings(lines, false))
 	fmt.Printf("    With jokers:    %d\n", aoc2307.CountWinnings(lines, true))
+
+	// Report how each hand was resolved with "aoc-2023-07 report".
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		games, err := aoc2307.CamelCards(true).Rank(lines)
+		shared.Die(err, "Rank")
+		for _, each := range games {
+			fmt.Printf(
+				"%4d %s -> %s %s\n",
+				each.Rank,
+				each.Hand,
+				each.Substitution,
+				each.Type.Name)
+		}
+	}
 	shared.Logger.Info("Done.")
 }
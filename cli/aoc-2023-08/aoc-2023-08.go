@@ -20,6 +20,8 @@ func main() {
 
 	fmt.Println("Step count:")
 	fmt.Printf("    Solo:    %d\n", aoc2308.CountSteps(lines))
-	fmt.Printf("    In sync: %d\n", aoc2308.CountStepsInSync(lines))
+	inSync, err := aoc2308.CountStepsInSync(lines)
+	shared.Die(err, "CountStepsInSync")
+	fmt.Printf("    In sync: %d\n", inSync)
 	shared.Logger.Info("Done.")
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/denarced/advent-of-code/lib/aoc2314"
 	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/replay"
 )
 
 func main() {
@@ -14,6 +15,7 @@ func main() {
 	id := "2023-14"
 	//revive:disable-next-line:defer
 	defer shared.SetupCPUProfiling(fmt.Sprintf("%s.profile", id))()
+	defer replay.SetupRecording(fmt.Sprintf("%s.replay", id), &aoc2314.FrameCb)()
 	lines, err := shared.ReadLinesFromFile(fmt.Sprintf("data/%s.txt", id))
 	shared.Die(err, "ReadLinesFromFile")
 
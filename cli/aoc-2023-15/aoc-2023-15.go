@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/denarced/advent-of-code/lib/aoc2315"
 	"github.com/denarced/advent-of-code/shared"
@@ -19,5 +20,9 @@ func main() {
 
 	fmt.Printf("Hash sum:       %d\n", aoc2315.SumHashes(lines))
 	fmt.Printf("Focusing power: %d\n", aoc2315.DeriveFocusingPower(lines))
+	// Dump the boxes after each step with "aoc-2023-15 steps".
+	if len(os.Args) > 1 && os.Args[1] == "steps" {
+		fmt.Print(aoc2315.DumpSteps(lines))
+	}
 	shared.Logger.Info("Done.")
 }
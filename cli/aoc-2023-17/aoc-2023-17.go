@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/denarced/advent-of-code/lib/aoc2317"
 	"github.com/denarced/advent-of-code/shared"
@@ -20,5 +22,17 @@ func main() {
 	fmt.Println("Least heat loss:")
 	fmt.Printf("    Normal: %d\n", aoc2317.DeriveLeastHeatLoss(lines, 1, 3))
 	fmt.Printf("    Ultra: %d\n", aoc2317.DeriveLeastHeatLoss(lines, 4, 10))
+
+	// Compare searches with "aoc-2023-17 compare".
+	if len(os.Args) > 1 && os.Args[1] == "compare" {
+		fmt.Println("Node expansions:")
+		for _, each := range []aoc2317.Search{aoc2317.Runners, aoc2317.Dijkstra, aoc2317.AStar} {
+			result := aoc2317.FindRoute(lines, 1, 3, each)
+			fmt.Printf("    %s: %d\n", each, result.Expansions)
+		}
+	}
+	result := aoc2317.FindRoute(lines, 1, 3, aoc2317.AStar)
+	fmt.Println("Route:")
+	fmt.Println(strings.Join(aoc2317.RenderRoute(lines, result.Route), "\n"))
 	shared.Logger.Info("Done.")
 }
@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/denarced/advent-of-code/lib/aoc2319"
 	"github.com/denarced/advent-of-code/shared"
@@ -20,5 +22,18 @@ func main() {
 
 	fmt.Printf("Ratings sum:  %d\n", aoc2319.SumRatings(lines))
 	fmt.Printf("Combinations: %d\n", aoc2319.Negotiate(lines, nil))
+
+	tree, err := aoc2319.Compile(lines)
+	shared.Die(err, "Compile")
+	bounds := aoc2319.NewBox(1, 4000, "x", "m", "a", "s")
+	fmt.Printf("Dead workflows: %s\n", strings.Join(tree.Dead(bounds), ", "))
+	fmt.Println("Unreachable rules:")
+	for _, each := range tree.Unreachable(bounds) {
+		fmt.Printf("    %s\n", each)
+	}
+	// Dump the decision tree with "aoc-2023-19 dump".
+	if len(os.Args) > 1 && os.Args[1] == "dump" {
+		fmt.Print(tree.Dump())
+	}
 	shared.Logger.Info("Done.")
 }
@@ -24,24 +24,8 @@ func main() {
 	fmt.Println("Total count:")
 	fmt.Printf("    Signals:                %d\n", tracker.LowCount*tracker.HighCount)
 
-	squad = aoc2320.NewFiringSquad(lines)
-	trackedComponents, expectedPulse := aoc2320.FindTracked(
-		squad.ComponentCallers,
-		"rx",
-		aoc2320.Low,
-	)
-	if len(trackedComponents) < 2 {
-		panic("expected more components to track")
-	}
-	monitor := aoc2320.NewRoundMonitor(trackedComponents, expectedPulse)
-	squad.RoundCb = monitor.Monitor
-	squad.Fire()
-	fmt.Printf(
-		"    For rx to receive low:  %d\n",
-		shared.DeriveLeastCommonMultiple(
-			monitor.Frequencies[0],
-			monitor.Frequencies[1],
-			monitor.Frequencies[2:]...),
-	)
+	activation, err := aoc2320.NewFiringSquad(lines).AnalyzeActivation("rx")
+	shared.Die(err, "AnalyzeActivation")
+	fmt.Printf("    For rx to receive low:  %d\n", activation.Presses)
 	shared.Logger.Info("Done.")
 }
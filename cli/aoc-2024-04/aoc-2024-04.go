@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/denarced/advent-of-code/lib/aoc2404"
 	"github.com/denarced/advent-of-code/shared"
@@ -17,5 +18,12 @@ func main() {
 	fmt.Printf("XMAS count:    %d\n", aoc2404.CountInTable(lines, "XMAS"))
 	fmt.Printf("MAX-MAX count: %d\n", aoc2404.CountWordCrosses(lines, "MAS"))
 
+	// Any other arguments are pattern files, e.g. lib/aoc2404/patterns/xmas.txt.
+	for _, each := range os.Args[1:] {
+		pattern, err := aoc2404.ReadPattern(each)
+		shared.Die(err, "ReadPattern")
+		fmt.Printf("%s count: %d\n", each, len(aoc2404.FindPattern(lines, pattern)))
+	}
+
 	shared.Logger.Info("Done.")
 }
@@ -14,9 +14,13 @@ func main() {
 	lines, err := shared.ReadLinesFromFile("data/2024-05.txt")
 	shared.Die(err, "ReadLinesFromFile")
 
+	correct, err := aoc2405.SumCorrectMiddlePageNumbers(lines)
+	shared.Die(err, "SumCorrectMiddlePageNumbers")
+	incorrect, err := aoc2405.SumIncorrectMiddlePageNumbers(lines)
+	shared.Die(err, "SumIncorrectMiddlePageNumbers")
 	fmt.Println("Middle page number sum:")
-	fmt.Printf("    Correct:   %d\n", aoc2405.SumCorrectMiddlePageNumbers(lines))
-	fmt.Printf("    Incorrect: %d\n", aoc2405.SumIncorrectMiddlePageNumbers(lines))
+	fmt.Printf("    Correct:   %d\n", correct)
+	fmt.Printf("    Incorrect: %d\n", incorrect)
 
 	shared.Logger.Info("Done.")
 }
@@ -5,12 +5,14 @@ import (
 
 	"github.com/denarced/advent-of-code/lib/aoc2406"
 	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/replay"
 	"github.com/denarced/gent"
 )
 
 func main() {
 	shared.InitLogging()
 	shared.Logger.Info("Start.")
+	defer replay.SetupRecording("2024-06.replay", &aoc2406.FrameCb)()
 
 	lines := gent.OrPanic2(shared.ReadLinesFromFile("data/2024-06.txt"))("ReadLinesFromFile")
 
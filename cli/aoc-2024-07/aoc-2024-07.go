@@ -17,8 +17,10 @@ func main() {
 
 	fmt.Println("Calibration sums:")
 	tab := strings.Repeat(" ", 3)
-	fmt.Println(tab, "Without concat:", aoc2407.DeriveCalibrationSum(lines, false))
-	fmt.Println(tab, "With concat:   ", aoc2407.DeriveCalibrationSum(lines, true))
+	withoutConcat := gent.OrPanic2(aoc2407.LookupOperators("+", "*"))("LookupOperators")
+	fmt.Println(tab, "Without concat:", aoc2407.DeriveCalibrationSum(lines, withoutConcat))
+	withConcat := gent.OrPanic2(aoc2407.LookupOperators("+", "*", "||"))("LookupOperators")
+	fmt.Println(tab, "With concat:   ", aoc2407.DeriveCalibrationSum(lines, withConcat))
 
 	shared.Logger.Info("Done.")
 }
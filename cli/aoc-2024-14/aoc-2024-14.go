@@ -2,20 +2,27 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/denarced/advent-of-code/lib/aoc2414"
 	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/replay"
 	"github.com/denarced/gent"
 )
 
 func main() {
 	shared.InitLogging()
 	shared.Logger.Info("Start.")
+	defer replay.SetupRecording("2024-14.replay", &aoc2414.FrameCb)()
 
 	lines := gent.OrPanic2(shared.ReadLinesFromFile("data/2024-14.txt"))("ReadLinesFromFile")
 
 	fmt.Println("Safety factor:", aoc2414.DeriveSafetyFactor(lines, 101, 103, 100))
 	fmt.Println("Steps to find Christmas tree:", aoc2414.FindChristmasTree(lines, 101, 103))
+	detection, err := aoc2414.DetectPattern(lines, 101, 103, aoc2414.Entropy)
+	shared.Die(err, "DetectPattern")
+	fmt.Printf("Entropy detection: step %d, confidence %.1f\n", detection.Step, detection.Confidence)
+	fmt.Println(strings.Join(detection.Frame, "\n"))
 
 	shared.Logger.Info("Done.")
 }
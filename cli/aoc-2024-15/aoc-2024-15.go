@@ -5,12 +5,14 @@ import (
 
 	"github.com/denarced/advent-of-code/lib/aoc2415"
 	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/replay"
 	"github.com/denarced/gent"
 )
 
 func main() {
 	shared.InitLogging()
 	shared.Logger.Info("Start.")
+	defer replay.SetupRecording("2024-15.replay", &aoc2415.FrameCb)()
 
 	lines := gent.OrPanic2(shared.ReadLinesFromFile("data/2024-15.txt"))("ReadLinesFromFile")
 
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/denarced/advent-of-code/lib/aoc2419"
+	"github.com/denarced/advent-of-code/shared"
+)
+
+func main() {
+	shared.InitLogging()
+	shared.Logger.Info("Start.")
+
+	lines, err := shared.ReadLinesFromFile("data/2024-19.txt")
+	shared.Die(err, "ReadLinesFromFile")
+
+	possible, ways := aoc2419.CountDesigns(lines)
+	fmt.Println("Designs:")
+	fmt.Printf("    Possible: %d\n", possible)
+	fmt.Printf("    Ways:     %s\n", ways)
+
+	shared.Logger.Info("Done.")
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/denarced/advent-of-code/lib/aoc2420"
+	"github.com/denarced/advent-of-code/shared"
+)
+
+func main() {
+	shared.InitLogging()
+	shared.Logger.Info("Start.")
+
+	lines, err := shared.ReadLinesFromFile("data/2024-20.txt")
+	shared.Die(err, "ReadLinesFromFile")
+
+	fmt.Println("Cheats saving at least 100 picoseconds:")
+	fmt.Printf("    2 picoseconds:  %d\n", aoc2420.CountCheats(lines, 2, 100))
+	fmt.Printf("    20 picoseconds: %d\n", aoc2420.CountCheats(lines, 20, 100))
+
+	shared.Logger.Info("Done.")
+}
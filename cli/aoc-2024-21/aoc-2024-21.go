@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/denarced/advent-of-code/lib/aoc2421"
+	"github.com/denarced/advent-of-code/shared"
+)
+
+func main() {
+	shared.InitLogging()
+	shared.Logger.Info("Start.")
+
+	lines, err := shared.ReadLinesFromFile("data/2024-21.txt")
+	shared.Die(err, "ReadLinesFromFile")
+
+	fmt.Println("Sum of complexities:")
+	fmt.Printf("    2 robots:  %d\n", aoc2421.SumComplexities(lines, 2))
+	fmt.Printf("    25 robots: %d\n", aoc2421.SumComplexities(lines, 25))
+
+	shared.Logger.Info("Done.")
+}
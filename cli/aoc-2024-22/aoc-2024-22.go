@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/denarced/advent-of-code/lib/aoc2422"
+	"github.com/denarced/advent-of-code/shared"
+)
+
+func main() {
+	shared.InitLogging()
+	shared.Logger.Info("Start.")
+
+	id := "2024-22"
+	//revive:disable-next-line:defer
+	defer shared.SetupCPUProfiling(fmt.Sprintf("%s.profile", id))()
+	lines, err := shared.ReadLinesFromFile(fmt.Sprintf("data/%s.txt", id))
+	shared.Die(err, "ReadLinesFromFile")
+
+	fmt.Println("Sum of secrets:", aoc2422.SumSecrets(lines, 2000))
+	bananas, changes := aoc2422.FindBestSequence(lines, 2000)
+	fmt.Printf("Most bananas:   %d with %v\n", bananas, changes)
+
+	shared.Logger.Info("Done.")
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/denarced/advent-of-code/lib/aoc2423"
+	"github.com/denarced/advent-of-code/shared"
+)
+
+func main() {
+	shared.InitLogging()
+	shared.Logger.Info("Start.")
+
+	lines, err := shared.ReadLinesFromFile("data/2024-23.txt")
+	shared.Die(err, "ReadLinesFromFile")
+
+	fmt.Println("Triangles with t:", aoc2423.CountTriangles(lines, "t"))
+	fmt.Println("Password:        ", aoc2423.DerivePassword(lines))
+
+	shared.Logger.Info("Done.")
+}
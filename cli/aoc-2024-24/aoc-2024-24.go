@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/denarced/advent-of-code/lib/aoc2424"
+	"github.com/denarced/advent-of-code/shared"
+)
+
+func main() {
+	shared.InitLogging()
+	shared.Logger.Info("Start.")
+
+	lines, err := shared.ReadLinesFromFile("data/2024-24.txt")
+	shared.Die(err, "ReadLinesFromFile")
+
+	fmt.Println("Z number:     ", aoc2424.DeriveZNumber(lines))
+	wires, err := aoc2424.DeriveSwappedWires(lines)
+	shared.Die(err, "DeriveSwappedWires")
+	fmt.Println("Swapped wires:", wires)
+	for _, each := range aoc2424.FindFaults(lines) {
+		fmt.Printf("    %s at bit %2d: %s\n", each.Wire, each.Bit, each.Reason)
+	}
+
+	shared.Logger.Info("Done.")
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/denarced/advent-of-code/lib/aoc2425"
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/inr"
+)
+
+func main() {
+	shared.InitLogging()
+	shared.Logger.Info("Start.")
+
+	lines, err := inr.ReadPath("data/2024-25.txt", inr.IncludeEmpty())
+	shared.Die(err, "ReadLinesFromFile")
+
+	fmt.Println("Fitting lock/key pairs:", aoc2425.CountFittingPairs(lines))
+
+	shared.Logger.Info("Done.")
+}
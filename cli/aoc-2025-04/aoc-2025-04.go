@@ -5,11 +5,13 @@ import (
 
 	"github.com/denarced/advent-of-code/lib/aoc2504"
 	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/replay"
 )
 
 func main() {
 	shared.InitLogging()
 	shared.Logger.Info("Start.")
+	defer replay.SetupRecording("2025-04.replay", &aoc2504.FrameCb)()
 
 	lines, err := shared.ReadLinesFromFile("data/2025-04.txt")
 	shared.Die(err, "ReadLinesFromFile")
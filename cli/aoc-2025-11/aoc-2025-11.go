@@ -15,8 +15,16 @@ func main() {
 	shared.Die(err, "ReadLinesFromFile")
 
 	fmt.Println("Path count:")
-	for _, each := range []string{"you", "svr"} {
-		fmt.Printf("    %s -> out: %d\n", each, aoc2511.CountPaths(lines, each))
+	for _, each := range []struct {
+		from      string
+		waypoints []string
+	}{
+		{"you", nil},
+		{"svr", []string{"fft", "dac"}},
+	} {
+		count, err := aoc2511.CountPaths(lines, each.from, "out", each.waypoints...)
+		shared.Die(err, "CountPaths")
+		fmt.Printf("    %s -> out: %s\n", each.from, count)
 	}
 	shared.Logger.Info("Done.")
 }
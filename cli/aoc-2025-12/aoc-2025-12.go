@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/denarced/advent-of-code/lib/aoc2512"
 	"github.com/denarced/advent-of-code/shared"
@@ -16,11 +17,18 @@ func main() {
 	lines, err := inr.ReadPath("data/2025-12.txt", inr.IncludeEmpty())
 	shared.Die(err, "ReadLinesFromFile")
 
-	count, err := aoc2512.CountFittingRegions(lines)
+	results, err := aoc2512.SolveRegions(lines, 10*time.Second)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 		os.Exit(2)
 	}
-	fmt.Printf("Region count: %d\n", count)
+	counts := map[aoc2512.Verdict]int{}
+	for _, each := range results {
+		counts[each.Verdict]++
+	}
+	fmt.Println("Region count:")
+	for _, each := range []aoc2512.Verdict{aoc2512.Fits, aoc2512.DoesNotFit, aoc2512.Unknown} {
+		fmt.Printf("    %-12s %d\n", each.String()+":", counts[each])
+	}
 	shared.Logger.Info("Done.")
 }
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage:
+    aoc graph [-format dot|mermaid] [-path a,b,c] [-cut a-b,c-d] [-answer] [-input file] year day
+    aoc replay [-gif out.gif] [-scale 4] [-every 1] [-fps 10] file.replay
+    aoc warehouse [-doubled] [-export out.txt] 2024-15.txt
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "graph":
+		err = runGraph(os.Args[2:], os.Stdout)
+	case "replay":
+		err = runReplay(os.Args[2:])
+	case "warehouse":
+		err = runWarehouse(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
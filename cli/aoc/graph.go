@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/denarced/advent-of-code/lib/aoc2308"
+	"github.com/denarced/advent-of-code/lib/aoc2320"
+	"github.com/denarced/advent-of-code/lib/aoc2323"
+	"github.com/denarced/advent-of-code/lib/aoc2511"
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/graph"
+	"github.com/denarced/advent-of-code/shared/inr"
+)
+
+var diagramCreators = map[string]func(lines []string) *graph.Diagram{
+	"2023-08": aoc2308.DrawNetwork,
+	"2023-20": aoc2320.DrawModules,
+	"2023-23": func(lines []string) *graph.Diagram { return aoc2323.DrawJunctions(lines, false) },
+	"2025-11": aoc2511.DrawDevices,
+}
+
+// answerCreators create diagrams with the puzzle's answer highlighted.
+var answerCreators = map[string]func(lines []string) *graph.Diagram{
+	"2023-23": func(lines []string) *graph.Diagram { return aoc2323.DrawJunctions(lines, true) },
+}
+
+func runGraph(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("graph", flag.ExitOnError)
+	format := flags.String("format", "dot", "output format: dot or mermaid")
+	path := flags.String("path", "", "comma separated nodes of a path to highlight")
+	cut := flags.String("cut", "", "comma separated edges, such as a-b, of a cut to highlight")
+	input := flags.String("input", "", "puzzle input, data/YYYY-DD.txt by default")
+	answer := flags.Bool("answer", false, "highlight the answer, such as the longest route of 2023-23")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return fmt.Errorf("expected year and day, got %d arguments", flags.NArg())
+	}
+	var year, day int
+	if _, err := fmt.Sscanf(flags.Arg(0)+" "+flags.Arg(1), "%d %d", &year, &day); err != nil {
+		return fmt.Errorf("invalid year or day: %w", err)
+	}
+	id := fmt.Sprintf("%d-%02d", year, day)
+	create, ok := diagramCreators[id]
+	if !ok {
+		return fmt.Errorf("no graph for %s", id)
+	}
+	if *answer {
+		if create, ok = answerCreators[id]; !ok {
+			return fmt.Errorf("no answer to highlight for %s", id)
+		}
+	}
+
+	shared.InitLogging()
+	shared.Logger.Info("Start.")
+	defer shared.Logger.Info("Done.")
+
+	filep := *input
+	if filep == "" {
+		filep = fmt.Sprintf("data/%s.txt", id)
+	}
+	lines, err := inr.ReadPath(filep, inr.IncludeEmpty())
+	if err != nil {
+		return err
+	}
+	// Blank lines separate blocks but the ones at the end would break grids.
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	diagram := create(lines)
+	if *path != "" {
+		if err := diagram.HighlightPath(strings.Split(*path, ",")...); err != nil {
+			return err
+		}
+	}
+	if *cut != "" {
+		var edges []shared.Pair[string]
+		for _, each := range strings.Split(*cut, ",") {
+			from, to, found := strings.Cut(each, "-")
+			if !found {
+				return fmt.Errorf("not an edge: %s", each)
+			}
+			edges = append(edges, shared.NewPair(from, to))
+		}
+		if err := diagram.HighlightCut(edges...); err != nil {
+			return err
+		}
+	}
+	var text string
+	switch *format {
+	case "dot":
+		text = diagram.DOT()
+	case "mermaid":
+		text = diagram.Mermaid()
+	default:
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+	_, err = io.WriteString(out, text)
+	return err
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunGraph(t *testing.T) {
+	run := func(name string, args []string, expected []string) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			var b strings.Builder
+
+			// EXERCISE
+			err := runGraph(args, &b)
+
+			// VERIFY
+			req.NoError(err)
+			for _, each := range expected {
+				req.Contains(b.String(), each)
+			}
+		})
+	}
+
+	// The input of 2023-08 has the instructions and the network in separate blocks.
+	run(
+		"dot",
+		[]string{"-input", "../../lib/aoc2308/testdata/in.txt", "2023", "8"},
+		[]string{"digraph {\n", `"AAA" -> "CCC" [label="R"];`})
+	run(
+		"mermaid with path",
+		[]string{
+			"-format", "mermaid",
+			"-path", "AAA,CCC,ZZZ",
+			"-input", "../../lib/aoc2308/testdata/in.txt",
+			"2023", "8",
+		},
+		[]string{"flowchart LR\n", "n2 -->|\"L\"| n6\n", "class n0,n2,n6 highlight\n"})
+	run(
+		"answer",
+		[]string{"-answer", "-input", "../../lib/aoc2323/testdata/in.txt", "2023", "23"},
+		[]string{`"19,3" -- "21,0" [label="5", color=red, penwidth=2];`})
+}
+
+func TestRunGraphErrors(t *testing.T) {
+	run := func(name string, args []string, expected string) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+
+			// EXERCISE
+			err := runGraph(args, &strings.Builder{})
+
+			// VERIFY
+			require.EqualError(t, err, expected)
+		})
+	}
+
+	run("no day", []string{"2023"}, "expected year and day, got 1 arguments")
+	run("no graph", []string{"2023", "1"}, "no graph for 2023-01")
+	run(
+		"no answer",
+		[]string{"-answer", "-input", "../../lib/aoc2308/testdata/in.txt", "2023", "8"},
+		"no answer to highlight for 2023-08")
+	run(
+		"unknown node",
+		[]string{"-path", "AAA,XXX", "-input", "../../lib/aoc2308/testdata/in.txt", "2023", "8"},
+		"unknown node: XXX")
+	run(
+		"format",
+		[]string{"-format", "svg", "-input", "../../lib/aoc2308/testdata/in.txt", "2023", "8"},
+		"unknown format: svg")
+}
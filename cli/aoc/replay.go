@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/render"
+	"github.com/denarced/advent-of-code/shared/replay"
+	"github.com/denarced/advent-of-code/shared/term"
+)
+
+func runReplay(args []string) error {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	gifPath := flags.String("gif", "", "export to an animated GIF instead of playing")
+	scale := flags.Int("scale", 4, "GIF pixels per cell")
+	every := flags.Int("every", 1, "GIF includes every nth frame")
+	fps := flags.Int("fps", 10, "frames per second")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected one replay file, got %d", flags.NArg())
+	}
+
+	shared.InitLogging()
+	shared.Logger.Info("Start.")
+	defer shared.Logger.Info("Done.")
+
+	rep, err := replay.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if rep.Len() == 0 {
+		return fmt.Errorf("no frames in %s", flags.Arg(0))
+	}
+	if *gifPath != "" {
+		return exportGif(rep, *gifPath, *scale, max(1, *every), max(1, *fps))
+	}
+	return play(rep, max(1, *fps))
+}
+
+func createScene(brd *shared.Board) *render.Scene {
+	scene := render.NewScene(brd)
+	for _, each := range "@^>v<" {
+		scene.Palette[each] = render.Red
+	}
+	for _, each := range "O[]" {
+		scene.Palette[each] = render.Yellow
+	}
+	for _, each := range "Xx" {
+		scene.Palette[each] = render.Blue
+	}
+	for _, each := range "123456789" {
+		scene.Palette[each] = render.Green
+	}
+	return scene
+}
+
+func exportGif(rep *replay.Replay, filep string, scale, every, fps int) error {
+	var scenes []*render.Scene
+	for i := 0; i < rep.Len(); i++ {
+		if i%every == 0 || i == rep.Len()-1 {
+			scenes = append(scenes, createScene(rep.Board(i).Copy()))
+		}
+	}
+	f, err := os.Create(filep)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := render.GIF(f, scenes, scale, max(1, 100/fps)); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d frames to %s.\n", len(scenes), filep)
+	return nil
+}
+
+func play(rep *replay.Replay, fps int) error {
+	restore, err := term.MakeRaw()
+	if err != nil {
+		return fmt.Errorf("failed to read keys from the terminal: %w", err)
+	}
+	defer restore()
+	keys := term.ReadKeys(bufio.NewReader(os.Stdin))
+	player := replay.NewPlayer(rep.Len(), fps)
+	for {
+		var b strings.Builder
+		b.WriteString(term.ClearScreen)
+		if err := render.ANSI(&b, createScene(rep.Board(player.Index))); err != nil {
+			return err
+		}
+		b.WriteString(player.Status() + "\n")
+		if _, err := os.Stdout.WriteString(b.String()); err != nil {
+			return err
+		}
+
+		var tick <-chan time.Time
+		if !player.Paused {
+			tick = time.After(time.Second / time.Duration(player.Fps))
+		}
+		select {
+		case key, ok := <-keys:
+			if !ok || player.Handle(key) {
+				return nil
+			}
+		case <-tick:
+			player.Tick()
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/denarced/advent-of-code/lib/aoc2415"
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/render"
+	"github.com/denarced/advent-of-code/shared/term"
+)
+
+var keyToMove = map[term.Key]rune{
+	term.KeyUp:    '^',
+	term.KeyDown:  'v',
+	term.KeyLeft:  '<',
+	term.KeyRight: '>',
+}
+
+func runWarehouse(args []string) error {
+	flags := flag.NewFlagSet("warehouse", flag.ExitOnError)
+	doubled := flags.Bool("doubled", false, "play the doubled warehouse")
+	exportPath := flags.String("export", "", "file that [e] exports the puzzle to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected one warehouse file, got %d", flags.NArg())
+	}
+
+	shared.InitLogging()
+	shared.Logger.Info("Start.")
+	defer shared.Logger.Info("Done.")
+
+	lines, err := shared.ReadLinesFromFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	w, err := aoc2415.NewWarehouse(lines, *doubled)
+	if err != nil {
+		return err
+	}
+	restore, err := term.MakeRaw()
+	if err != nil {
+		return fmt.Errorf("failed to read keys from the terminal: %w", err)
+	}
+	defer restore()
+
+	message := ""
+	keys := term.ReadKeys(bufio.NewReader(os.Stdin))
+	for {
+		var b strings.Builder
+		b.WriteString(term.ClearScreen)
+		if err := render.ANSI(&b, createScene(w.Board())); err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "GPS sum: %d  moves: %d  %s\n", w.Gps(), len(w.Moves()), message)
+		b.WriteString("[arrows] move [u] undo [r] redo [e] export [q] quit\n")
+		if _, err := os.Stdout.WriteString(b.String()); err != nil {
+			return err
+		}
+
+		key, ok := <-keys
+		if !ok {
+			return nil
+		}
+		message = ""
+		switch key {
+		case 'u':
+			message = shared.Or(w.Undo(), "", "nothing to undo")
+		case 'r':
+			message = shared.Or(w.Redo(), "", "nothing to redo")
+		case 'e':
+			message = exportWarehouse(w, *exportPath)
+		case 'q', term.KeyEscape:
+			fmt.Println("Moves:", w.Moves())
+			return nil
+		default:
+			if move, ok := keyToMove[key]; ok {
+				if err := w.Move(move); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func exportWarehouse(w *aoc2415.Warehouse, filep string) string {
+	if filep == "" {
+		return "moves: " + w.Moves()
+	}
+	content := strings.Join(w.Export(), "\n") + "\n"
+	if err := os.WriteFile(filep, []byte(content), 0644); err != nil {
+		return fmt.Sprintf("export failed: %s", err)
+	}
+	return "exported to " + filep
+}
@@ -1,13 +1,11 @@
 package aoc2307
 
 import (
-	"fmt"
 	"testing"
 
 	"github.com/denarced/advent-of-code/shared"
 	"github.com/denarced/advent-of-code/shared/inr"
 	"github.com/denarced/gent"
-	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -26,140 +24,132 @@ func TestCountWinnings(t *testing.T) {
 	run(true, 5905)
 }
 
-func createGame(hand string) game {
-	return game{cards: parseCards(hand)}
-}
-func withHandType(aGame game, kind handType) game {
-	aGame.handType = kind
-	return aGame
-}
+func TestRank(t *testing.T) {
+	run := func(name string, rules *Rules, lines []string, expected []string) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
 
-func TestSortGamesWithoutJokers(t *testing.T) {
-	shared.InitTestLogging(t)
-	req := require.New(t)
-	pair := createGame("22345")
-	highCard := createGame("9TJQK")
-	fiveAces := createGame("AAAAA")
-	fiveKings := createGame("KKKKK")
-	games := []game{
-		pair,
-		fiveAces,
-		fiveKings,
-		highCard,
-	}
+			// EXERCISE
+			games, err := rules.Rank(lines)
 
-	// EXERCISE
-	sorted := sortGames(games, false)
+			// VERIFY
+			req.NoError(err)
+			req.Equal(expected, gent.Map(games, func(g Game) string { return g.Hand }))
+			for i, each := range games {
+				req.Equal(i+1, each.Rank)
+			}
+		})
+	}
 
-	// VERIFY
-	req.Equal(
-		[]game{
-			withHandType(highCard, handHighCard),
-			withHandType(pair, handOnePair),
-			withHandType(fiveKings, handFive),
-			withHandType(fiveAces, handFive),
-		},
-		sorted,
-	)
+	run(
+		"without jokers",
+		CamelCards(false),
+		[]string{"22345 1", "AAAAA 2", "KKKKK 3", "9TJQK 4"},
+		[]string{"9TJQK", "22345", "KKKKK", "AAAAA"})
+	// Three of a kind starting with J should have lower value.
+	run(
+		"with jokers",
+		CamelCards(true),
+		[]string{"22J34 1", "J2234 2"},
+		[]string{"J2234", "22J34"})
+	run(
+		"sorted",
+		&Rules{Order: "23456789TJQKA", Types: camelTypes, TieBreak: Sorted},
+		[]string{"A2345 1", "2345K 2", "A2344 3"},
+		[]string{"2345K", "A2345", "A2344"})
+	run(
+		"grouped",
+		&Rules{Order: "23456789TJQKA", Types: camelTypes, TieBreak: Grouped},
+		[]string{"AA222 1", "33KK3 2", "2KK2K 3"},
+		[]string{"AA222", "33KK3", "2KK2K"})
 }
 
-func TestSortGamesWithJokers(t *testing.T) {
-	shared.InitTestLogging(t)
-	req := require.New(t)
-	lowTrips := createGame("J2234")
-	highTrips := createGame("22J34")
-	games := []game{
-		highTrips,
-		lowTrips,
+func TestResolve(t *testing.T) {
+	run := func(rules *Rules, hand, expectedType, expectedSubstitution string) {
+		t.Run(hand, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+
+			// EXERCISE
+			resolution, err := rules.Resolve(hand)
+
+			// VERIFY
+			req.NoError(err)
+			req.Equal(expectedType, resolution.Type.Name)
+			req.Equal(expectedSubstitution, resolution.Substitution)
+		})
 	}
 
-	// EXERCISE
-	sorted := sortGames(games, true)
+	jokers := CamelCards(true)
+	run(jokers, "T55J5", "four of a kind", "T5555")
+	run(jokers, "6JJJJ", "five of a kind", "66666")
+	run(jokers, "JJJJJ", "five of a kind", "AAAAA")
+	run(jokers, "2233J", "full house", "22333")
+	run(jokers, "234JJ", "three of a kind", "23444")
+	run(jokers, "2234J", "three of a kind", "22342")
+	// Doesn't appear to be possible to switch jokers to two pairs. It always turns into trips.
+	run(jokers, "2345J", "one pair", "23455")
+	run(CamelCards(false), "2345J", "high card", "2345J")
 
-	// VERIFY
-	req.Equal(
-		[]game{
-			// Three of a kind starting with J should have lower value.
-			withHandType(lowTrips, handThree),
-			withHandType(highTrips, handThree),
+	// Straights of six different cards, weaker than a pair.
+	straights := &Rules{
+		Order:     "*123456",
+		Wildcards: "*",
+		Types: []HandType{
+			{Name: "nothing"},
+			{Name: "straight", Pattern: []int{1, 1, 1, 1, 1, 1}},
+			{Name: "pair", Pattern: []int{2}},
 		},
-		sorted,
-	)
+		TieBreak: InOrder,
+	}
+	run(straights, "1234*5", "pair", "123455")
+	run(straights, "1*1235", "pair", "111235")
+
+	// A weird variant where a pair beats more.
+	pairs := &Rules{
+		Order:     "J23456789TQKA",
+		Wildcards: "J",
+		Types:     []HandType{{Name: "nothing"}, {Name: "pairs", Pattern: []int{2, 2}}},
+		TieBreak:  InOrder,
+	}
+	run(pairs, "2234J", "pairs", "22344")
+	run(pairs, "234JJ", "pairs", "23443")
 }
 
-func TestDeriveHighestHand(t *testing.T) {
-	run := func(name string, aGame game, expected handType) {
+func TestRulesErrors(t *testing.T) {
+	run := func(name string, rules *Rules, expected string) {
 		t.Run(name, func(t *testing.T) {
 			shared.InitTestLogging(t)
-			actual := deriveHighestHand(aGame)
-			require.Equal(t, fmt.Sprint(expected), fmt.Sprint(actual))
+
+			// EXERCISE
+			_, err := rules.Rank([]string{"23456 1"})
+
+			// VERIFY
+			require.EqualError(t, err, expected)
 		})
 	}
+
 	run(
-		"T55J5 to four of a kind",
-		game{
-			cards:    parseCards("T55J5"),
-			handType: handThree,
-		},
-		handFour)
-	run(
-		"6JJJJ to five of a kind",
-		game{
-			cards:    parseCards("6JJJJ"),
-			handType: handFour,
-		},
-		handFive)
+		"twice",
+		&Rules{Order: "234562", Types: camelTypes, TieBreak: InOrder},
+		"card 2 is twice in the order")
 	run(
-		"2233J to full house",
-		game{
-			cards:    parseCards("2233J"),
-			handType: handTwoPair,
-		},
-		handFullHouse)
+		"wildcard",
+		&Rules{Order: "23456", Wildcards: "J", Types: camelTypes, TieBreak: InOrder},
+		"wildcard J isn't in the order")
+	run("no types", &Rules{Order: "23456", TieBreak: InOrder}, "no hand types")
 	run(
-		"234JJ to three of a kind",
-		game{
-			cards:    parseCards("234JJ"),
-			handType: handOnePair,
-		},
-		handThree)
+		"zero",
+		&Rules{Order: "23456", Types: []HandType{{"zero", []int{0}}}, TieBreak: InOrder},
+		"hand type zero has count 0")
+	run("no tie-break", &Rules{Order: "23456", Types: camelTypes}, "no tie-break")
 	run(
-		"2234J to three of a kind",
-		game{
-			cards:    parseCards("2234J"),
-			handType: handOnePair,
-		},
-		handThree)
-	// Doesn't appear to be possible to switch jokers to two pairs. It always turns into trips.
+		"unknown card",
+		&Rules{Order: "2345", Types: camelTypes, TieBreak: InOrder},
+		"unknown card 6 in 23456")
 	run(
-		"2345J to one pair",
-		game{
-			cards:    parseCards("2345J"),
-			handType: handHighCard,
-		},
-		handOnePair)
-}
-
-func TestCards(t *testing.T) {
-	var tests = []struct {
-		aCard card
-		str   string
-	}{
-		{card2, "2"},
-		{card9, "9"},
-		{cardT, "T"},
-		{cardA, "A"},
-	}
-	for _, tt := range tests {
-		t.Run(tt.str, func(t *testing.T) {
-			ass := assert.New(t)
-			ass.Equal(tt.str, fmt.Sprint(tt.aCard))
-			ass.Equal(tt.aCard, toCard(rune(tt.str[0])))
-		})
-	}
-
-	ass := assert.New(t)
-	for _, each := range []int{-1, 0, 1, 15} {
-		ass.Equal("unknown", fmt.Sprint(card(each)))
-	}
+		"no type",
+		&Rules{Order: "23456", Types: []HandType{{"pair", []int{2}}}, TieBreak: InOrder},
+		"no hand type matches 23456")
 }
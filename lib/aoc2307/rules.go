@@ -0,0 +1,275 @@
+package aoc2307
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// HandType is a type of hand. Pattern is the multiset of equal card counts that the hand has to
+// contain, e.g. [3 2] for a full house. Extra cards don't matter.
+type HandType struct {
+	Name    string
+	Pattern []int
+}
+
+// TieBreak orders hands of the same type.
+type TieBreak func(rules *Rules, a, b Resolution) int
+
+// Rules is a variant of Camel Cards.
+type Rules struct {
+	// Order lists the card labels from the weakest.
+	Order string
+	// Wildcards are the labels that can stand in for any card when the hand type is resolved.
+	Wildcards string
+	// Types lists the hand types from the weakest. A hand is of the strongest type it matches.
+	Types    []HandType
+	TieBreak TieBreak
+}
+
+// Resolution is a hand with its resolved type. Substitution is the hand with the wildcards
+// replaced by the cards that give the hand its type.
+type Resolution struct {
+	Hand         string
+	Substitution string
+	Type         HandType
+	// Strength is the index of Type in the rules.
+	Strength int
+}
+
+var camelTypes = []HandType{
+	{Name: "high card"},
+	{Name: "one pair", Pattern: []int{2}},
+	{Name: "two pair", Pattern: []int{2, 2}},
+	{Name: "three of a kind", Pattern: []int{3}},
+	{Name: "full house", Pattern: []int{3, 2}},
+	{Name: "four of a kind", Pattern: []int{4}},
+	{Name: "five of a kind", Pattern: []int{5}},
+}
+
+// CamelCards returns the rules of the puzzle. With jokers, J is a wildcard and the weakest card.
+func CamelCards(jokers bool) *Rules {
+	if jokers {
+		return &Rules{
+			Order:     "J23456789TQKA",
+			Wildcards: "J",
+			Types:     camelTypes,
+			TieBreak:  InOrder,
+		}
+	}
+	return &Rules{Order: "23456789TJQKA", Types: camelTypes, TieBreak: InOrder}
+}
+
+// Validate checks that the rules make sense.
+func (v *Rules) Validate() error {
+	for i, each := range v.Order {
+		if strings.ContainsRune(v.Order[i+1:], each) {
+			return fmt.Errorf("card %c is twice in the order", each)
+		}
+	}
+	for _, each := range v.Wildcards {
+		if !strings.ContainsRune(v.Order, each) {
+			return fmt.Errorf("wildcard %c isn't in the order", each)
+		}
+	}
+	if len(v.Types) == 0 {
+		return errors.New("no hand types")
+	}
+	for _, each := range v.Types {
+		for _, count := range each.Pattern {
+			if count < 1 {
+				return fmt.Errorf("hand type %s has count %d", each.Name, count)
+			}
+		}
+	}
+	if v.TieBreak == nil {
+		return errors.New("no tie-break")
+	}
+	return nil
+}
+
+func (v *Rules) strength(card byte) int {
+	return strings.IndexByte(v.Order, card)
+}
+
+// Resolve resolves the type of the hand. Wildcards are substituted so that the type is the
+// strongest possible.
+func (v *Rules) Resolve(hand string) (Resolution, error) {
+	var wildcards int
+	counts := map[byte]int{}
+	for i := range len(hand) {
+		if v.strength(hand[i]) < 0 {
+			return Resolution{}, fmt.Errorf("unknown card %c in %s", hand[i], hand)
+		}
+		if strings.IndexByte(v.Wildcards, hand[i]) >= 0 {
+			wildcards++
+		} else {
+			counts[hand[i]]++
+		}
+	}
+	var groups []group
+	for card, count := range counts {
+		groups = append(groups, group{card: card, count: count})
+	}
+	v.sortGroups(groups)
+
+	best := Resolution{Hand: hand, Strength: -1}
+	for _, each := range v.distribute(groups, wildcards) {
+		strength := v.matchType(each)
+		if strength > best.Strength {
+			best.Strength = strength
+			best.Substitution = substitute(hand, v.Wildcards, each, groups)
+		}
+	}
+	if best.Strength < 0 {
+		return Resolution{}, fmt.Errorf("no hand type matches %s", hand)
+	}
+	best.Type = v.Types[best.Strength]
+	return best, nil
+}
+
+type group struct {
+	card  byte
+	count int
+}
+
+// sortGroups sorts the groups from the largest, the stronger card first on ties.
+func (v *Rules) sortGroups(groups []group) {
+	slices.SortFunc(groups, func(a, b group) int {
+		if a.count != b.count {
+			return b.count - a.count
+		}
+		return v.strength(b.card) - v.strength(a.card)
+	})
+}
+
+// distribute returns the ways to add wildcards to the groups. A wildcard either joins a group or
+// starts a new one, with the strongest card that isn't in the hand yet. Earlier ways add to the
+// larger groups.
+func (v *Rules) distribute(groups []group, wildcards int) [][]group {
+	if wildcards == 0 {
+		return [][]group{groups}
+	}
+	var ways [][]group
+	for i := range groups {
+		next := slices.Clone(groups)
+		next[i].count++
+		ways = append(ways, v.distribute(next, wildcards-1)...)
+	}
+	for i := len(v.Order) - 1; i >= 0; i-- {
+		card := v.Order[i]
+		if strings.IndexByte(v.Wildcards, card) >= 0 ||
+			slices.ContainsFunc(groups, func(g group) bool { return g.card == card }) {
+			continue
+		}
+		next := append(slices.Clone(groups), group{card: card, count: 1})
+		ways = append(ways, v.distribute(next, wildcards-1)...)
+		break
+	}
+	return ways
+}
+
+// matchType returns the strength of the strongest type that the groups match, or -1.
+func (v *Rules) matchType(groups []group) int {
+	counts := make([]int, len(groups))
+	for i, each := range groups {
+		counts[i] = each.count
+	}
+	slices.SortFunc(counts, func(a, b int) int { return b - a })
+	for i := len(v.Types) - 1; i >= 0; i-- {
+		pattern := slices.Clone(v.Types[i].Pattern)
+		slices.SortFunc(pattern, func(a, b int) int { return b - a })
+		if len(pattern) > len(counts) {
+			continue
+		}
+		matches := true
+		for j, each := range pattern {
+			if each > counts[j] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return i
+		}
+	}
+	return -1
+}
+
+// substitute replaces the wildcards of the hand with the cards that were added to the original
+// groups.
+func substitute(hand, wildcards string, groups, original []group) string {
+	var added []byte
+	for _, each := range groups {
+		count := each.count
+		if index := slices.IndexFunc(original, func(g group) bool {
+			return g.card == each.card
+		}); index >= 0 {
+			count -= original[index].count
+		}
+		for range count {
+			added = append(added, each.card)
+		}
+	}
+	result := []byte(hand)
+	for i := range result {
+		if strings.IndexByte(wildcards, result[i]) >= 0 {
+			result[i], added = added[0], added[1:]
+		}
+	}
+	return string(result)
+}
+
+// Compare orders the hands by type and then by the tie-break.
+func (v *Rules) Compare(a, b Resolution) int {
+	if a.Strength != b.Strength {
+		return a.Strength - b.Strength
+	}
+	return v.TieBreak(v, a, b)
+}
+
+// InOrder compares the cards of the hands as they are, from the first card. It's the puzzle's
+// tie-break.
+func InOrder(rules *Rules, a, b Resolution) int {
+	return rules.compareCards(a.Hand, b.Hand)
+}
+
+// Sorted compares the cards of the hands from the strongest.
+func Sorted(rules *Rules, a, b Resolution) int {
+	sortCards := func(hand string) string {
+		cards := []byte(hand)
+		slices.SortFunc(cards, func(x, y byte) int { return rules.strength(y) - rules.strength(x) })
+		return string(cards)
+	}
+	return rules.compareCards(sortCards(a.Hand), sortCards(b.Hand))
+}
+
+// Grouped compares the substituted hands poker style: the largest group first, e.g. the three of
+// a kind of a full house before the pair.
+func Grouped(rules *Rules, a, b Resolution) int {
+	sortCards := func(hand string) string {
+		counts := map[byte]int{}
+		for i := range len(hand) {
+			counts[hand[i]]++
+		}
+		cards := []byte(hand)
+		slices.SortFunc(cards, func(x, y byte) int {
+			if counts[x] != counts[y] {
+				return counts[y] - counts[x]
+			}
+			return rules.strength(y) - rules.strength(x)
+		})
+		return string(cards)
+	}
+	return rules.compareCards(sortCards(a.Substitution), sortCards(b.Substitution))
+}
+
+func (v *Rules) compareCards(a, b string) int {
+	for i := range min(len(a), len(b)) {
+		if diff := v.strength(a[i]) - v.strength(b[i]); diff != 0 {
+			return diff
+		}
+	}
+	return len(a) - len(b)
+}
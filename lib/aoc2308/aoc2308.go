@@ -1,11 +1,14 @@
 package aoc2308
 
 import (
+	"errors"
 	"fmt"
-	"math"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/graph"
 )
 
 func getNext(nod *node, r rune) *node {
@@ -45,12 +48,9 @@ func CountSteps(lines []string) int {
 	return i
 }
 
-type pathSpec struct {
-	firstCount  int
-	repeatCount int
-}
-
-func CountStepsInSync(lines []string) int {
+// CountStepsInSync counts the steps until all ghosts that start from nodes ending with A are on
+// nodes ending with Z at the same time.
+func CountStepsInSync(lines []string) (int, error) {
 	path, nodes := parseLines(lines)
 	var starters []string
 	for key := range nodes {
@@ -58,50 +58,117 @@ func CountStepsInSync(lines []string) int {
 			starters = append(starters, key)
 		}
 	}
+	slices.Sort(starters)
 	shared.Logger.Info(
 		"Count steps in sync.",
 		"path length", len(path),
 		"node count", len(nodes),
 		"starter count", len(starters),
 	)
+	if len(starters) == 0 {
+		return 0, errors.New("no starting nodes")
+	}
 
-	pathSpecs := make([]pathSpec, len(starters))
+	cycles := make([]cycle, len(starters))
 	for i, each := range starters {
-		pathSpecs[i] = findPathSpecs(path, nodes[each])
-	}
-	shared.Logger.Info("Path specs derived.", "specs", pathSpecs)
-	findLowest := func() int {
-		low := math.MaxInt
-		var index int
-		for i, each := range pathSpecs {
-			if each.firstCount < low {
-				index = i
-				low = each.firstCount
-			}
+		cycles[i] = analyzeCycle(path, nodes[each])
+		if len(cycles[i].hits) == 0 {
+			return 0, fmt.Errorf("ghost starting from %s never reaches a Z node", each)
+		}
+	}
+	shared.Logger.Info("Cycles analyzed.", "cycles", cycles)
+	count, ok := findSync(cycles)
+	if !ok {
+		return 0, fmt.Errorf("ghosts are never on Z nodes at the same time: %v", cycles)
+	}
+	shared.Logger.Info("Steps counted.", "count", count)
+	return count, nil
+}
+
+// cycle describes a ghost's walk. After offset steps the walk repeats every period steps.
+type cycle struct {
+	offset int
+	period int
+	// Steps, before offset+period, at which the ghost is on a Z node.
+	hits []int
+}
+
+type walkState struct {
+	nod   *node
+	index int
+}
+
+func analyzeCycle(path string, nod *node) cycle {
+	steps := []rune(path)
+	visited := map[walkState]int{}
+	var hits []int
+	for i := 0; ; i++ {
+		state := walkState{nod: nod, index: i % len(steps)}
+		if first, ok := visited[state]; ok {
+			return cycle{offset: first, period: i - first, hits: hits}
 		}
-		return index
-	}
-	areAllEqual := func() bool {
-		value := -1
-		for _, each := range pathSpecs {
-			if value < 0 {
-				value = each.firstCount
-			} else if value != each.firstCount {
-				return false
+		visited[state] = i
+		if nod.name[len(nod.name)-1] == 'Z' {
+			hits = append(hits, i)
+		}
+		nod = getNext(nod, steps[state.index])
+	}
+}
+
+// isHit checks whether the ghost is on a Z node at step.
+func (v cycle) isHit(step int) bool {
+	if step >= v.offset {
+		step = v.offset + (step-v.offset)%v.period
+	}
+	_, found := slices.BinarySearch(v.hits, step)
+	return found
+}
+
+// findSync finds the first step, after the start, at which all cycles hit. Hits before a cycle
+// starts happen only once so they're checked directly. Every combination of the hits within the
+// cycles is combined with the Chinese remainder theorem.
+func findSync(cycles []cycle) (int, bool) {
+	best := -1
+	for _, c := range cycles {
+		for _, hit := range c.hits {
+			if hit == 0 || hit >= c.offset || best >= 0 && hit >= best {
+				continue
+			}
+			if !slices.ContainsFunc(cycles, func(other cycle) bool { return !other.isHit(hit) }) {
+				best = hit
 			}
 		}
-		return true
 	}
-	for {
-		index := findLowest()
-		pathSpecs[index].firstCount += pathSpecs[index].repeatCount
-		shared.Logger.Debug("Path specs.", "specs", pathSpecs)
-		if areAllEqual() {
-			count := pathSpecs[0].firstCount
-			shared.Logger.Info("Steps counted.", "count", count)
-			return count
+
+	var combine func(index int, combined shared.Congruence, lowest int)
+	combine = func(index int, combined shared.Congruence, lowest int) {
+		if index == len(cycles) {
+			step := combined.Remainder
+			if step < lowest {
+				step += (lowest - step + combined.Modulus - 1) / combined.Modulus * combined.Modulus
+			}
+			if best < 0 || step < best {
+				best = step
+			}
+			return
+		}
+		c := cycles[index]
+		for _, hit := range c.hits {
+			if hit < c.offset {
+				continue
+			}
+			next, ok := shared.CombineCongruences(
+				combined,
+				shared.Congruence{Remainder: hit % c.period, Modulus: c.period},
+			)
+			if !ok {
+				continue
+			}
+			combine(index+1, next, max(lowest, hit))
 		}
 	}
+	combine(0, shared.Congruence{Remainder: 0, Modulus: 1}, 1)
+	return best, best >= 0
 }
 
 type node struct {
@@ -192,28 +259,30 @@ func parseTargetNodes(s string) (left, right string) {
 	return
 }
 
-func findPathSpecs(path string, nod *node) (spec pathSpec) {
-	steps := []rune(path)
-	var i int
-	for {
-		each := steps[i%len(steps)]
-		i++
-		nod = getNext(nod, each)
-		if nod.name[len(nod.name)-1] == 'Z' {
-			spec.firstCount = i
-			break
+// DrawNetwork creates a diagram of the nodes with edges labelled L and R. Nodes ending with A are
+// starts and nodes ending with Z are ends.
+func DrawNetwork(lines []string) *graph.Diagram {
+	_, nodes := parseLines(lines)
+	shared.Logger.Info("Draw network.", "node count", len(nodes))
+	names := slices.Sorted(maps.Keys(nodes))
+	diagram := graph.NewDiagram(true)
+	for _, each := range names {
+		kind := "node"
+		switch each[len(each)-1] {
+		case 'A':
+			kind = "start"
+		case 'Z':
+			kind = "end"
 		}
+		diagram.AddNode(each, kind)
 	}
-	count := 0
-	for {
-		each := steps[i%len(steps)]
-		i++
-		count++
-		nod = getNext(nod, each)
-		if nod.name[len(nod.name)-1] == 'Z' {
-			spec.repeatCount = count
-			break
+	for _, each := range names {
+		nod := nodes[each]
+		if nod.left == nil {
+			continue
 		}
+		diagram.AddEdge(each, nod.left.name, "L")
+		diagram.AddEdge(each, nod.right.name, "R")
 	}
-	return
+	return diagram
 }
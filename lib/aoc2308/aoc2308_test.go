@@ -21,7 +21,98 @@ func TestCountStepsInSync(t *testing.T) {
 	req := require.New(t)
 	lines, err := inr.ReadPath("testdata/in2.txt", inr.IncludeEmpty())
 	req.NoError(err, "failed to read test data")
-	req.Equal(6, CountStepsInSync(lines))
+	count, err := CountStepsInSync(lines)
+	req.NoError(err)
+	req.Equal(6, count)
+}
+
+func TestCountStepsInSyncWithCycles(t *testing.T) {
+	run := func(name string, lines []string, expected int, expectedErr string) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+
+			// EXERCISE
+			count, err := CountStepsInSync(append([]string{"L", ""}, lines...))
+
+			// VERIFY
+			if expectedErr != "" {
+				req.ErrorContains(err, expectedErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(expected, count)
+		})
+	}
+
+	// 11A: 11Z at 2, 5, 8, ... 22A: 22Z at 1, 3, 5, ...
+	run(
+		"offset cycles",
+		[]string{
+			"11A = (11B, 11B)",
+			"11B = (11Z, 11Z)",
+			"11Z = (11C, 11C)",
+			"11C = (11B, 11B)",
+			"22A = (22Z, 22Z)",
+			"22Z = (22B, 22B)",
+			"22B = (22Z, 22Z)",
+		},
+		5,
+		"",
+	)
+	// 11A: Z nodes at 1, 2, 5, 6, 9, ... 22A: 22Z at 3, 6, 9, ...
+	run(
+		"several hits in cycle",
+		[]string{
+			"11A = (11Z, 11Z)",
+			"11Z = (12Z, 12Z)",
+			"12Z = (11B, 11B)",
+			"11B = (11C, 11C)",
+			"11C = (11Z, 11Z)",
+			"22A = (22B, 22B)",
+			"22B = (22C, 22C)",
+			"22C = (22Z, 22Z)",
+			"22Z = (22B, 22B)",
+		},
+		6,
+		"",
+	)
+	// 11A: 11Z only at 1 before a cycle without Z nodes. 22A: 22Z at 1, 2, 3, ...
+	run(
+		"hit before cycle",
+		[]string{
+			"11A = (11Z, 11Z)",
+			"11Z = (11B, 11B)",
+			"11B = (11B, 11B)",
+			"22A = (22Z, 22Z)",
+			"22Z = (22Z, 22Z)",
+		},
+		1,
+		"",
+	)
+	// 11A: 11Z at 2, 4, 6, ... 22A: 22Z at 1, 3, 5, ...
+	run(
+		"never in sync",
+		[]string{
+			"11A = (11B, 11B)",
+			"11B = (11Z, 11Z)",
+			"11Z = (11B, 11B)",
+			"22A = (22Z, 22Z)",
+			"22Z = (22B, 22B)",
+			"22B = (22Z, 22Z)",
+		},
+		0,
+		"never on Z nodes at the same time",
+	)
+	run(
+		"no Z",
+		[]string{
+			"11A = (11B, 11B)",
+			"11B = (11A, 11A)",
+		},
+		0,
+		"11A never reaches a Z node",
+	)
 }
 
 func TestParseLines(t *testing.T) {
@@ -53,7 +144,7 @@ func TestParseLines(t *testing.T) {
 	req.Nil(cccNode.right, "CCC right is nil")
 }
 
-func TestFindPathSpecs(t *testing.T) {
+func TestAnalyzeCycle(t *testing.T) {
 	shared.InitTestLogging(t)
 	req := require.New(t)
 
@@ -69,11 +160,56 @@ func TestFindPathSpecs(t *testing.T) {
 	nod := nodes["AAA"]
 
 	// EXERCISE
-	spec := findPathSpecs(path, nod)
+	actual := analyzeCycle(path, nod)
 
 	// VERIFY
-	req.Equal(pathSpec{
-		firstCount:  4,
-		repeatCount: 2,
-	}, spec)
+	req.Equal(cycle{offset: 3, period: 2, hits: []int{4}}, actual)
+	req.False(actual.isHit(3))
+	req.True(actual.isHit(4))
+	req.False(actual.isHit(5))
+	req.True(actual.isHit(1_000))
+}
+
+func TestFindSync(t *testing.T) {
+	run := func(name string, cycles []cycle, expected int, expectedOk bool) {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+
+			// EXERCISE
+			actual, ok := findSync(cycles)
+
+			// VERIFY
+			req.Equal(expectedOk, ok)
+			req.Equal(expected, actual)
+		})
+	}
+
+	run("single", []cycle{{offset: 1, period: 3, hits: []int{2}}}, 2, true)
+	run(
+		"coprime",
+		[]cycle{
+			{offset: 1, period: 3, hits: []int{2}},
+			{offset: 1, period: 5, hits: []int{4}},
+		},
+		14,
+		true,
+	)
+	run(
+		"lower bound",
+		[]cycle{
+			{offset: 10, period: 2, hits: []int{10}},
+			{offset: 1, period: 1, hits: []int{1}},
+		},
+		10,
+		true,
+	)
+	run(
+		"not coprime",
+		[]cycle{
+			{offset: 0, period: 4, hits: []int{1}},
+			{offset: 0, period: 6, hits: []int{2}},
+		},
+		-1,
+		false,
+	)
 }
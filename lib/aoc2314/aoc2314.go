@@ -9,9 +9,15 @@ const (
 	emptySpace rune = '.'
 )
 
+// FrameCb receives the board after each tilt when set.
+var FrameCb func(brd *shared.Board)
+
 func CountTotalLoad(lines []string, cycleCount int) int {
 	shared.Logger.Info("Count total load.")
 	brd := shared.NewBoard(lines)
+	if FrameCb != nil {
+		FrameCb(brd)
+	}
 	if cycleCount <= 0 {
 		moveRocks(brd, shared.RealNorth)
 	} else {
@@ -100,6 +106,9 @@ func moveRocks(brd *shared.Board, direction shared.Direction) {
 			shared.Logger.Debug("Move rock.", "from", each, "to", dest)
 		}
 	})
+	if FrameCb != nil {
+		FrameCb(brd)
+	}
 	if shared.IsDebugEnabled() {
 		shared.Logger.Info(
 			"Rocks moved.",
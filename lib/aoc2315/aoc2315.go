@@ -1,6 +1,7 @@
 package aoc2315
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -43,96 +44,52 @@ func parseLines(lines []string, cb func(string)) {
 	}
 }
 
-type lens struct {
-	label string
-	focal int
-}
-
-type lensBox struct {
-	lenses []lens
-}
-
-func (v *lensBox) find(label string) (int, bool) {
-	for i, each := range v.lenses {
-		if each.label == label {
-			return i, true
-		}
-	}
-	return 0, false
-}
-
-func (v *lensBox) add(label string, focal int) {
-	index, exists := v.find(label)
-	if exists {
-		v.lenses[index].focal = focal
-		return
-	}
-	v.lenses = append(v.lenses, lens{
-		label: label,
-		focal: focal,
-	})
-}
-
-func (v *lensBox) remove(label string) {
-	index, exists := v.find(label)
-	if !exists {
-		return
-	}
-	shortened := make([]lens, 0, len(v.lenses)-1)
-	if index > 0 {
-		shortened = v.lenses[:index]
-	}
-	if len(v.lenses) > index+1 {
-		shortened = append(shortened, v.lenses[index+1:]...)
+// apply applies an operation such as "rn=1" or "cm-" to the lenses.
+func apply(lenses *HashMap[int], operation string) {
+	shared.Logger.Debug("Process command.", "operation", operation)
+	label, kind, focal := splitCommand(operation)
+	switch kind {
+	case opAdd:
+		lenses.Put(label, focal)
+	case opRemove:
+		lenses.Delete(label)
+	default:
+		panic("unknown opType")
 	}
-	if len(shortened) == 0 {
-		shortened = nil
-	}
-	v.lenses = shortened
-}
-
-func getBox(boxes []*lensBox, index int) *lensBox {
-	box := boxes[index]
-	if box == nil {
-		box = new(lensBox)
-		boxes[index] = box
-	}
-	return box
 }
 
 func DeriveFocusingPower(lines []string) int {
 	shared.Logger.Info("Derive focusing power.")
-	boxes := make([]*lensBox, boxCount)
+	lenses := NewHashMap[int]()
 	parseLines(lines, func(each string) {
-		shared.Logger.Debug("Process command.", "each", each)
-		label, kind, focal := splitCommand(each)
-		boxIndex := hash(label)
-		box := getBox(boxes, boxIndex)
-		switch kind {
-		case opAdd:
-			box.add(label, focal)
-		case opRemove:
-			box.remove(label)
-		default:
-			panic("unknown opType")
-		}
+		apply(lenses, each)
 	})
 
-	shared.Logger.Info("Sum lenses to derive focusing power.")
+	shared.Logger.Info("Sum lenses to derive focusing power.", "report", lenses.Report())
 	var power int
-	for i, box := range boxes {
-		if box == nil {
-			continue
-		}
-		for j, each := range box.lenses {
-			pwr := (i + 1) * (j + 1) * each.focal
-			power += pwr
-		}
-	}
+	lenses.Range(func(box, slot int, entry Entry[int]) bool {
+		power += (box + 1) * (slot + 1) * entry.Value
+		return true
+	})
 	shared.Logger.Info("Focusing power derived.", "power", power)
 	return power
 }
 
+// DumpSteps dumps the boxes after each operation like the puzzle does.
+func DumpSteps(lines []string) string {
+	lenses := NewHashMap[int]()
+	var steps []string
+	parseLines(lines, func(each string) {
+		apply(lenses, each)
+		if lenses.Len() == 0 {
+			steps = append(steps, fmt.Sprintf("After %q:\n", each))
+		} else {
+			steps = append(steps, fmt.Sprintf("After %q:\n%s\n", each, lenses))
+		}
+	})
+	return strings.Join(steps, "\n")
+}
+
 func splitCommand(cmd string) (label string, kind opType, focal int) {
 	pieces := strings.Split(cmd, "=")
 	if len(pieces) == 2 {
@@ -1,7 +1,8 @@
 package aoc2315
 
 import (
-	"strings"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/denarced/advent-of-code/shared"
@@ -43,54 +44,22 @@ func TestParseLines(t *testing.T) {
 		pieces)
 }
 
-func TestLensBoxAdd(t *testing.T) {
-	box := &lensBox{}
-	box.add("hg", 6)
-	box.add("au", 2)
-	req := require.New(t)
-	req.Equal(
-		[]lens{
-			{
-				label: "hg",
-				focal: 6,
-			},
-			{
-				label: "au",
-				focal: 2,
-			},
-		},
-		box.lenses)
-}
-
-func TestLensBoxRemove(t *testing.T) {
-	run := func(expectedIndexes []int, labels ...string) {
-		t.Run(strings.Join(labels, ","), func(t *testing.T) {
+func TestDumpSteps(t *testing.T) {
+	run := func(input, steps string) {
+		t.Run(input, func(t *testing.T) {
+			shared.InitTestLogging(t)
 			req := require.New(t)
-			box := &lensBox{}
-			box.add("fe", 7)
-			box.add("hg", 3)
-			box.add("ag", 9)
-
-			var expected []lens
-			for _, i := range expectedIndexes {
-				expected = append(expected, box.lenses[i])
-			}
+			lines, err := inr.ReadPath(filepath.Join("testdata", input))
+			req.NoError(err)
+			expected, err := os.ReadFile(filepath.Join("testdata", steps))
+			req.NoError(err)
 
-			// EXERCISE
-			for _, each := range labels {
-				box.remove(each)
-			}
-
-			// VERIFY
-			req.Equal(expected, box.lenses)
+			// EXERCISE & VERIFY
+			req.Equal(string(expected), DumpSteps(lines))
 		})
 	}
 
-	run([]int{1, 2}, "fe")
-	run([]int{0, 2}, "hg")
-	run([]int{0, 1}, "ag")
-	run([]int{2}, "fe", "hg")
-	run(nil, "fe", "hg", "ag")
-
-	require.Equal(t, 2, len(strings.Split("a-", "-")))
+	run("in.txt", "steps.txt")
+	// The lenses are all removed in between.
+	run("emptied.txt", "emptied-steps.txt")
 }
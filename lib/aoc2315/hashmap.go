@@ -0,0 +1,128 @@
+package aoc2315
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Entry is a key and its value in a HashMap.
+type Entry[V any] struct {
+	Key   string
+	Value V
+}
+
+// HashMap is the puzzle's HASHMAP: keys go to one of 256 boxes by their HASH and each box keeps
+// its entries in insertion order.
+type HashMap[V any] struct {
+	boxes [boxCount][]Entry[V]
+	size  int
+}
+
+func NewHashMap[V any]() *HashMap[V] {
+	return &HashMap[V]{}
+}
+
+func (v *HashMap[V]) find(key string) (box, slot int) {
+	box = hash(key)
+	slot = slices.IndexFunc(v.boxes[box], func(e Entry[V]) bool { return e.Key == key })
+	return
+}
+
+func (v *HashMap[V]) Get(key string) (V, bool) {
+	box, slot := v.find(key)
+	if slot < 0 {
+		var zero V
+		return zero, false
+	}
+	return v.boxes[box][slot].Value, true
+}
+
+// Put sets the value of the key. An existing key keeps its place in the box.
+func (v *HashMap[V]) Put(key string, value V) {
+	box, slot := v.find(key)
+	if slot >= 0 {
+		v.boxes[box][slot].Value = value
+		return
+	}
+	v.boxes[box] = append(v.boxes[box], Entry[V]{Key: key, Value: value})
+	v.size++
+}
+
+// Delete deletes the key and reports whether it existed. The entries after it move forward.
+func (v *HashMap[V]) Delete(key string) bool {
+	box, slot := v.find(key)
+	if slot < 0 {
+		return false
+	}
+	v.boxes[box] = slices.Delete(v.boxes[box], slot, slot+1)
+	v.size--
+	return true
+}
+
+func (v *HashMap[V]) Len() int {
+	return v.size
+}
+
+// Range calls cb for each entry, box by box in insertion order, until cb returns false.
+func (v *HashMap[V]) Range(cb func(box, slot int, entry Entry[V]) bool) {
+	for box, entries := range v.boxes {
+		for slot, each := range entries {
+			if !cb(box, slot, each) {
+				return
+			}
+		}
+	}
+}
+
+// Report describes how the entries are spread over the boxes.
+type Report struct {
+	Size      int
+	UsedBoxes int
+	// Load is the average number of entries per box.
+	Load float64
+	// Longest is the number of entries in the fullest box.
+	Longest int
+	// Collisions is the number of entries that share a box with an earlier entry.
+	Collisions int
+}
+
+func (v Report) String() string {
+	return fmt.Sprintf(
+		"size %d, boxes used %d/%d, load %.2f, longest box %d, collisions %d",
+		v.Size,
+		v.UsedBoxes,
+		boxCount,
+		v.Load,
+		v.Longest,
+		v.Collisions)
+}
+
+func (v *HashMap[V]) Report() Report {
+	report := Report{Size: v.size, Load: float64(v.size) / boxCount}
+	for _, entries := range v.boxes {
+		if len(entries) == 0 {
+			continue
+		}
+		report.UsedBoxes++
+		report.Longest = max(report.Longest, len(entries))
+		report.Collisions += len(entries) - 1
+	}
+	return report
+}
+
+// String formats the non-empty boxes like the puzzle does, e.g. "Box 0: [rn 1] [cm 2]".
+func (v *HashMap[V]) String() string {
+	var lines []string
+	for box, entries := range v.boxes {
+		if len(entries) == 0 {
+			continue
+		}
+		pieces := make([]string, len(entries))
+		for i, each := range entries {
+			pieces[i] = fmt.Sprintf("[%s %v]", each.Key, each.Value)
+		}
+		lines = append(lines, fmt.Sprintf("Box %d: %s", box, strings.Join(pieces, " ")))
+	}
+	return strings.Join(lines, "\n")
+}
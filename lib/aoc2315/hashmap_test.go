@@ -0,0 +1,110 @@
+package aoc2315
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func collectEntries[V any](m *HashMap[V]) []Entry[V] {
+	var entries []Entry[V]
+	m.Range(func(_, _ int, entry Entry[V]) bool {
+		entries = append(entries, entry)
+		return true
+	})
+	return entries
+}
+
+func TestHashMapPut(t *testing.T) {
+	req := require.New(t)
+	m := NewHashMap[int]()
+
+	// EXERCISE
+	m.Put("rn", 6)
+	m.Put("cm", 2)
+	m.Put("rn", 4)
+
+	// VERIFY
+	req.Equal(2, m.Len())
+	value, ok := m.Get("rn")
+	req.True(ok)
+	req.Equal(4, value)
+	_, ok = m.Get("xx")
+	req.False(ok)
+	// Both are in box 0 so insertion order shows.
+	req.Equal(0, hash("rn"))
+	req.Equal(0, hash("cm"))
+	req.Equal([]Entry[int]{{Key: "rn", Value: 4}, {Key: "cm", Value: 2}}, collectEntries(m))
+}
+
+func TestHashMapDelete(t *testing.T) {
+	run := func(expected []string, keys ...string) {
+		t.Run(strings.Join(keys, ","), func(t *testing.T) {
+			req := require.New(t)
+			m := NewHashMap[string]()
+			// All are in box 3.
+			for _, each := range []string{"pc", "ot", "ab"} {
+				m.Put(each, each)
+			}
+
+			// EXERCISE
+			for _, each := range keys {
+				req.True(m.Delete(each))
+			}
+
+			// VERIFY
+			req.False(m.Delete("xx"))
+			req.Equal(len(expected), m.Len())
+			var actual []string
+			for _, each := range collectEntries(m) {
+				actual = append(actual, each.Key)
+			}
+			req.Equal(expected, actual)
+		})
+	}
+
+	run([]string{"ot", "ab"}, "pc")
+	run([]string{"pc", "ab"}, "ot")
+	run([]string{"pc", "ot"}, "ab")
+	run([]string{"ab"}, "pc", "ot")
+	run(nil, "pc", "ot", "ab")
+}
+
+func TestHashMapRange(t *testing.T) {
+	req := require.New(t)
+	m := NewHashMap[int]()
+	for i, each := range []string{"pc", "rn", "ot", "qp", "ab"} {
+		m.Put(each, i)
+	}
+	var positions [][2]int
+	var keys []string
+
+	// EXERCISE
+	m.Range(func(box, slot int, entry Entry[int]) bool {
+		positions = append(positions, [2]int{box, slot})
+		keys = append(keys, entry.Key)
+		return len(keys) < 4
+	})
+
+	// VERIFY
+	req.Equal([]string{"rn", "qp", "pc", "ot"}, keys)
+	req.Equal([][2]int{{0, 0}, {1, 0}, {3, 0}, {3, 1}}, positions)
+}
+
+func TestHashMapReport(t *testing.T) {
+	req := require.New(t)
+	m := NewHashMap[int]()
+	for i, each := range []string{"rn", "cm", "qp", "pc", "ot", "ab"} {
+		m.Put(each, i)
+	}
+
+	// EXERCISE
+	report := m.Report()
+
+	// VERIFY
+	req.Equal(Report{Size: 6, UsedBoxes: 3, Load: 6.0 / 256, Longest: 3, Collisions: 3}, report)
+	req.Equal(
+		"size 6, boxes used 3/256, load 0.02, longest box 3, collisions 3",
+		report.String())
+}
@@ -2,6 +2,7 @@ package aoc2317
 
 import (
 	"math"
+	"slices"
 	"strings"
 
 	"github.com/denarced/advent-of-code/shared"
@@ -14,7 +15,7 @@ func DeriveLeastHeatLoss(lines []string, minJump, maxJump int) int {
 		"Derive least heat loss.",
 		"width", brd.GetWidth(),
 		"height", brd.GetHeight())
-	return findMinimumHeat(brd, shared.Loc{X: brd.GetWidth() - 1}, minJump, maxJump)
+	return findMinimumHeat(brd, shared.Loc{X: brd.GetWidth() - 1}, minJump, maxJump).HeatLoss
 }
 
 type hop struct {
@@ -30,7 +31,7 @@ type runner struct {
 }
 
 //revive:disable-next-line:function-length
-func findMinimumHeat(brd *shared.Board, target shared.Loc, minJump, maxJump int) int {
+func findMinimumHeat(brd *shared.Board, target shared.Loc, minJump, maxJump int) Result {
 	shared.Logger.Info(
 		"Start running.",
 		"target", target.ToString(),
@@ -63,6 +64,8 @@ func findMinimumHeat(brd *shared.Board, target shared.Loc, minJump, maxJump int)
 	runnerCount := 1
 
 	minHeat := math.MaxInt
+	var best *runner
+	expansions := 0
 	finishRace := func(r *runner) {
 		if shared.IsDebugEnabled() {
 			shared.Logger.Debug(
@@ -72,6 +75,7 @@ func findMinimumHeat(brd *shared.Board, target shared.Loc, minJump, maxJump int)
 		}
 		if minHeat > r.sum {
 			minHeat = r.sum
+			best = r
 			shared.Logger.Info(
 				"New record achieved.",
 				"sum", r.sum,
@@ -92,6 +96,7 @@ func findMinimumHeat(brd *shared.Board, target shared.Loc, minJump, maxJump int)
 			}
 			continue
 		}
+		expansions++
 		hops = hops[:0]
 		hops = filterHops(
 			hasher,
@@ -141,7 +146,20 @@ func findMinimumHeat(brd *shared.Board, target shared.Loc, minJump, maxJump int)
 		}
 	}
 	shared.Logger.Info("Done running.", "runner count", runnerCount, "min heat", minHeat)
-	return minHeat
+	result := Result{HeatLoss: minHeat, Expansions: expansions}
+	if best != nil {
+		result.Route = expandRoute(toCorners(best.tail))
+	}
+	return result
+}
+
+func toCorners(head *shared.Link[shared.Loc]) []shared.Loc {
+	var corners []shared.Loc
+	for l := head; l != nil; l = l.Parent {
+		corners = append(corners, l.Item)
+	}
+	slices.Reverse(corners)
+	return corners
 }
 
 func doInBetween(from, to shared.Loc, callback func(loc shared.Loc)) {
@@ -121,3 +121,89 @@ func TestDeriveNextHops(t *testing.T) {
 			{loc: shared.Loc{X: 3, Y: 1}, dir: shared.RealEast},
 		})
 }
+
+func TestFindRoute(t *testing.T) {
+	run := func(search Search, minJump, maxJump, expected int) {
+		name := fmt.Sprintf("%s %d - %d", search, minJump, maxJump)
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			lines, err := inr.ReadPath("testdata/in.txt")
+			req.NoError(err)
+
+			// EXERCISE
+			result := FindRoute(lines, minJump, maxJump, search)
+
+			// VERIFY
+			req.Equal(expected, result.HeatLoss)
+			req.Positive(result.Expansions)
+			brd := shared.NewBoard(lines)
+			req.Equal(shared.Loc{Y: brd.GetHeight() - 1}, result.Route[0])
+			req.Equal(shared.Loc{X: brd.GetWidth() - 1}, result.Route[len(result.Route)-1])
+			heat := 0
+			for i, each := range result.Route[1:] {
+				req.Equal(1, measureDistance(result.Route[i], each), "step %d", i)
+				heat += brd.GetIntOrDie(each)
+			}
+			req.Equal(expected, heat)
+		})
+	}
+
+	for _, each := range []Search{Runners, Dijkstra, AStar} {
+		run(each, 1, 3, 102)
+		run(each, 4, 10, 94)
+	}
+}
+
+func TestFindRouteExpansions(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	lines, err := inr.ReadPath("testdata/in.txt")
+	req.NoError(err)
+
+	// EXERCISE
+	dijkstra := FindRoute(lines, 1, 3, Dijkstra)
+	aStar := FindRoute(lines, 1, 3, AStar)
+
+	// VERIFY
+	req.Less(aStar.Expansions, dijkstra.Expansions)
+}
+
+func TestRenderRoute(t *testing.T) {
+	req := require.New(t)
+	shared.InitTestLogging(t)
+	lines := []string{
+		"123",
+		"456",
+		"789",
+	}
+	route := []shared.Loc{
+		{X: 0, Y: 2},
+		{X: 1, Y: 2},
+		{X: 1, Y: 1},
+		{X: 0, Y: 1},
+		{X: 0, Y: 0},
+		{X: 1, Y: 0},
+		{X: 2, Y: 0},
+		{X: 2, Y: 1},
+	}
+
+	// EXERCISE
+	actual := RenderRoute(lines, route)
+
+	// VERIFY
+	req.Equal([]string{"1>3", "<v^", "v>>"}, actual)
+}
+
+func BenchmarkFindRoute(b *testing.B) {
+	shared.InitNullLogging()
+	lines, _ := inr.ReadPath("testdata/in.txt")
+
+	for _, each := range []Search{Runners, Dijkstra, AStar} {
+		b.Run(each.String(), func(b *testing.B) {
+			for range b.N {
+				FindRoute(lines, 1, 3, each)
+			}
+		})
+	}
+}
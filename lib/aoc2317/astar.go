@@ -0,0 +1,205 @@
+package aoc2317
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/render"
+)
+
+// Search is the algorithm used to find the route.
+type Search int
+
+const (
+	// Runners is the original breadth-first search of runners.
+	Runners Search = iota
+	// Dijkstra is A* without the heuristic.
+	Dijkstra
+	// AStar is guided by the cheapest cell times the Manhattan distance to the target.
+	AStar
+)
+
+func (v Search) String() string {
+	switch v {
+	case Runners:
+		return "runners"
+	case Dijkstra:
+		return "Dijkstra"
+	case AStar:
+		return "A*"
+	}
+	return "unknown"
+}
+
+// Result is the route with the least heat loss. Expansions is the number of search nodes that
+// were expanded to find it, to compare searches.
+type Result struct {
+	HeatLoss int
+	// Route is every cell from the top left corner to the bottom right one.
+	Route      []shared.Loc
+	Expansions int
+}
+
+// FindRoute finds the route with the least heat loss.
+func FindRoute(lines []string, minJump, maxJump int, search Search) Result {
+	brd := shared.NewBoard(lines)
+	shared.Logger.Info(
+		"Find route.",
+		"width", brd.GetWidth(),
+		"height", brd.GetHeight(),
+		"search", search)
+	target := shared.Loc{X: brd.GetWidth() - 1}
+	var result Result
+	switch search {
+	case Runners:
+		result = findMinimumHeat(brd, target, minJump, maxJump)
+	case Dijkstra:
+		result = findWithAStar(brd, target, minJump, maxJump, false)
+	case AStar:
+		result = findWithAStar(brd, target, minJump, maxJump, true)
+	default:
+		panic(fmt.Sprintf("Unknown search: %d.", search))
+	}
+	shared.Logger.Info(
+		"Route found.",
+		"search", search,
+		"heat loss", result.HeatLoss,
+		"expansions", result.Expansions)
+	return result
+}
+
+// RenderRoute marks the route on the board with the direction moved into each cell.
+func RenderRoute(lines []string, route []shared.Loc) []string {
+	return render.Lines(render.NewScene(shared.NewBoard(lines), render.Path(route, render.Red)))
+}
+
+// node is a search node: where the crucible is and whether it got there moving horizontally. The
+// next move is always a turn.
+type node struct {
+	loc        shared.Loc
+	horizontal bool
+}
+
+type queueItem struct {
+	node     node
+	heat     int
+	estimate int
+}
+
+type queue []queueItem
+
+func (v queue) Len() int           { return len(v) }
+func (v queue) Less(i, j int) bool { return v[i].estimate < v[j].estimate }
+func (v queue) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
+func (v *queue) Push(x any)        { *v = append(*v, x.(queueItem)) }
+
+func (v *queue) Pop() any {
+	old := *v
+	item := old[len(old)-1]
+	*v = old[:len(old)-1]
+	return item
+}
+
+func findWithAStar(
+	brd *shared.Board,
+	target shared.Loc,
+	minJump, maxJump int,
+	guided bool,
+) Result {
+	cheapest := 0
+	if guided {
+		cheapest = findCheapestCell(brd)
+	}
+	estimate := func(loc shared.Loc) int {
+		return cheapest * measureDistance(loc, target)
+	}
+	start := shared.Loc{Y: brd.GetHeight() - 1}
+	heats := map[node]int{}
+	parents := map[node]node{}
+	q := &queue{}
+	for _, horizontal := range []bool{true, false} {
+		n := node{loc: start, horizontal: horizontal}
+		heats[n] = 0
+		heap.Push(q, queueItem{node: n, estimate: estimate(start)})
+	}
+	expansions := 0
+	for q.Len() > 0 {
+		item := heap.Pop(q).(queueItem)
+		if item.heat > heats[item.node] {
+			continue
+		}
+		expansions++
+		if item.node.loc == target {
+			return Result{
+				HeatLoss:   item.heat,
+				Route:      traceRoute(parents, item.node),
+				Expansions: expansions,
+			}
+		}
+		dirs := []shared.Direction{shared.RealEast, shared.RealWest}
+		if item.node.horizontal {
+			dirs = []shared.Direction{shared.RealNorth, shared.RealSouth}
+		}
+		for _, dir := range dirs {
+			loc := item.node.loc
+			heat := item.heat
+			for step := 1; step <= maxJump; step++ {
+				loc = loc.Delta(shared.Loc(dir))
+				cell, ok := brd.GetInt(loc)
+				if !ok {
+					break
+				}
+				heat += cell
+				if step < minJump {
+					continue
+				}
+				next := node{loc: loc, horizontal: dir.Y == 0}
+				if previous, ok := heats[next]; ok && previous <= heat {
+					continue
+				}
+				heats[next] = heat
+				parents[next] = item.node
+				heap.Push(q, queueItem{node: next, heat: heat, estimate: heat + estimate(loc)})
+			}
+		}
+	}
+	return Result{HeatLoss: math.MaxInt, Expansions: expansions}
+}
+
+func findCheapestCell(brd *shared.Board) int {
+	cheapest := math.MaxInt
+	brd.Iter(func(loc shared.Loc, _ rune) bool {
+		cheapest = min(cheapest, brd.GetIntOrDie(loc))
+		return true
+	})
+	return cheapest
+}
+
+func traceRoute(parents map[node]node, last node) []shared.Loc {
+	var corners []shared.Loc
+	for n, ok := last, true; ok; n, ok = parents[n] {
+		corners = append(corners, n.loc)
+	}
+	slices.Reverse(corners)
+	return expandRoute(corners)
+}
+
+// expandRoute expands the corners of a route to every cell of it.
+func expandRoute(corners []shared.Loc) []shared.Loc {
+	if len(corners) == 0 {
+		return nil
+	}
+	route := []shared.Loc{corners[0]}
+	for i := 1; i < len(corners); i++ {
+		doInBetween(corners[i-1], corners[i], func(loc shared.Loc) {
+			route = append(route, loc)
+		})
+		if corners[i] != corners[i-1] {
+			route = append(route, corners[i])
+		}
+	}
+	return route
+}
@@ -2,8 +2,6 @@ package aoc2319
 
 import (
 	"fmt"
-	"slices"
-	"sort"
 	"strconv"
 	"strings"
 
@@ -12,36 +10,20 @@ import (
 
 func SumRatings(lines []string) int {
 	shared.Logger.Info("Sum ratings.", "line count", len(lines))
-	nameToFlow, parts := parseLines(lines)
-	if shared.IsDebugEnabled() {
-		shared.Logger.Debug("Got situation.", "worksflows", nameToFlow, "parts", parts)
-	}
-	steps := make([]step, len(parts))
-	for i, each := range parts {
-		steps[i] = step{workflowName: "in", aPart: each}
+	_, parts := parseLines(lines)
+	tree, err := Compile(lines)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to compile workflows: %s.", err))
 	}
 	var sum int
-	var aStep step
-	var count int
-	for len(steps) > 0 {
-		count++
-		aStep, steps = steps[0], steps[1:]
-		nextStep := processStep(aStep, nameToFlow[aStep.workflowName])
-		switch nextStep.workflowName {
-		case "R":
-			shared.Logger.Debug("Rejected.", "part", nextStep.aPart)
-			continue
-		case "A":
-			sub := nextStep.aPart.sum()
-			if shared.IsDebugEnabled() {
-				shared.Logger.Debug("Add to the sum.", "sub", sub)
-			}
+	for _, each := range parts {
+		if tree.Classify(each) {
+			sub := each.sum()
+			shared.Logger.Debug("Add to the sum.", "part", each, "sub", sub)
 			sum += sub
-		default:
-			steps = append(steps, nextStep)
 		}
 	}
-	shared.Logger.Info("Got ratings sum.", "sum", sum, "loop count", count)
+	shared.Logger.Info("Got ratings sum.", "sum", sum)
 	return sum
 }
 
@@ -56,11 +38,6 @@ func parseLines(lines []string) (map[string]workflow, []part) {
 	return workflows, parts
 }
 
-type step struct {
-	workflowName string
-	aPart        part
-}
-
 type workflow struct {
 	name  string
 	specs []spec
@@ -163,135 +140,32 @@ func parseIntOrDie(num string) int {
 	return val
 }
 
-func processStep(aStep step, flow workflow) step {
-	for _, each := range flow.specs {
-		if each.endComplete {
-			return step{workflowName: each.dest, aPart: aStep.aPart}
-		}
-		value := aStep.aPart.pick(each.attr)
-		if each.less {
-			if value < each.value {
-				return step{workflowName: each.dest, aPart: aStep.aPart}
-			}
-		} else if value > each.value {
-			return step{workflowName: each.dest, aPart: aStep.aPart}
-		}
-	}
-	panic("my ass")
-}
-
 type restriction struct {
 	low  int
 	high int
 }
 
-func (v restriction) String() string {
-	return fmt.Sprintf("(%d-%d)", v.low, v.high)
-}
-
 type policy map[string]restriction
 
-func (v policy) intersection(other policy) (result policy) {
-	result = policy{}
-	for key, this := range v {
-		if this.high < other[key].low || other[key].high < this.low {
-			result = nil
-			return
-		}
-		result[key] = restriction{
-			low:  max(this.low, other[key].low),
-			high: min(this.high, other[key].high),
-		}
-	}
-	return
-}
-
-func (v policy) String() (res string) {
-	for key, val := range v {
-		s := fmt.Sprintf("%s%s", key, val.String())
-		if res != "" {
-			res += " "
-		}
-		res += s
+// Negotiate counts the accepted parts within the default policy, 1-4000 for x, m, a and s, or the
+// one that genDefaultPolicy generates.
+func Negotiate(lines []string, genDefaultPolicy func() policy) int {
+	tree, err := Compile(lines)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to compile workflows: %s.", err))
 	}
-	res = "[" + res + "]"
-	return
-}
-
-func (v policy) multiply() int {
-	var prod int
-	for _, each := range v {
-		length := each.high - each.low + 1
-		if prod == 0 {
-			prod = length
-		} else {
-			prod *= length
+	bounds := NewBox(1, 4000, "x", "m", "a", "s")
+	if genDefaultPolicy != nil {
+		bounds = Box{}
+		for key, each := range genDefaultPolicy() {
+			bounds[key] = Range{Low: each.low, High: each.high}
 		}
 	}
-	return prod
-}
-
-func Negotiate(lines []string, genDefaultPolicy func() policy) int {
-	workflows, _ := parseLines(lines)
-	var policies []policy
-	pierce(
-		workflows,
-		"in",
-		nil,
-		func(link *shared.Link[comparison]) {
-			pol := derivePolicy(link, genDefaultPolicy)
-			if pol != nil {
-				policies = append(policies, pol)
-			}
-		})
-	shared.Logger.Info("Start to count result.", "policy count", len(policies))
-	land := countLand(policies)
+	land := tree.Count(bounds)
 	shared.Logger.Info("Land derived.", "land", land)
 	return land
 }
 
-func derivePolicy(link *shared.Link[comparison], genDefaultPolicy func() policy) policy {
-	var pol policy
-	if genDefaultPolicy == nil {
-		pol = policy{
-			"x": restriction{low: 1, high: 4000},
-			"a": restriction{low: 1, high: 4000},
-			"m": restriction{low: 1, high: 4000},
-			"s": restriction{low: 1, high: 4000},
-		}
-	} else {
-		pol = genDefaultPolicy()
-	}
-	for link != nil {
-		rest, ok := pol[link.Item.attr]
-		if !ok {
-			panic("no such restriction: " + link.Item.attr)
-		}
-		rest, ok = cutRestriction(rest, link.Item)
-		if !ok {
-			return nil
-		}
-		pol[link.Item.attr] = rest
-		link = link.Parent
-	}
-	return pol
-}
-
-func cutRestriction(rest restriction, comp comparison) (restriction, bool) {
-	if comp.less {
-		next := comp.val - 1
-		if next < rest.high {
-			rest.high = next
-		}
-	} else {
-		next := comp.val + 1
-		if next > rest.low {
-			rest.low = next
-		}
-	}
-	return rest, rest.low <= rest.high
-}
-
 type comparison struct {
 	attr string
 	val  int
@@ -305,117 +179,3 @@ func (v comparison) String() string {
 	}
 	return fmt.Sprintf("%s%s%d", v.attr, op, v.val)
 }
-
-func (v comparison) reverse() comparison {
-	var val int
-	if v.less {
-		val = v.val - 1
-	} else {
-		val = v.val + 1
-	}
-	return comparison{
-		attr: v.attr,
-		val:  val,
-		less: !v.less,
-	}
-}
-
-func pierce(
-	workflows map[string]workflow,
-	name string,
-	comparisons *shared.Link[comparison],
-	cb func(*shared.Link[comparison]),
-) {
-	flow := workflows[name]
-	for _, each := range flow.specs {
-		if each.endComplete {
-			if each.dest == "A" {
-				cb(comparisons)
-			} else if each.dest != "R" {
-				pierce(workflows, each.dest, comparisons, cb)
-			}
-			continue
-		}
-		c := comparison{attr: each.attr, val: each.value, less: each.less}
-		if each.dest != "R" {
-			next := shared.AddLink(comparisons, c)
-			if each.dest == "A" {
-				cb(next)
-			} else {
-				pierce(workflows, each.dest, next, cb)
-			}
-		}
-		comparisons = shared.AddLink(comparisons, c.reverse())
-	}
-}
-
-func countLand(policies []policy) int {
-	keys := collectSortedPolicyKeys(policies)
-	if len(keys) == 0 {
-		return 0
-	}
-	sortPolicies(policies, keys)
-	primary := keys[0]
-	var overlap int
-	for i := 0; i < len(policies)-1; i++ {
-		first := policies[i]
-		for j := i + 1; j < len(policies); j++ {
-			second := policies[j]
-			if first[primary].high < second[primary].low {
-				break
-			}
-			if cross := first.intersection(second); cross != nil {
-				overlap += cross.multiply()
-			}
-		}
-	}
-	var total int
-	for _, each := range policies {
-		total += each.multiply()
-	}
-	return total - overlap
-}
-
-func collectSortedPolicyKeys(policies []policy) []string {
-	if len(policies) == 0 {
-		return nil
-	}
-	m := map[string]int{}
-	for _, each := range policies {
-		for k := range each {
-			m[k] = 0
-		}
-	}
-	if len(m) == 0 {
-		return nil
-	}
-	keys := make([]string, len(m))
-	var i int
-	for k := range m {
-		keys[i] = k
-		i++
-	}
-	sort.Strings(keys)
-	return keys
-}
-
-func sortPolicies(policies []policy, keys []string) {
-	getLow := func(pol policy, key string) int {
-		value, ok := pol[key]
-		if ok {
-			return value.low
-		}
-		panic("policy doesn't have dimension " + key)
-	}
-	slices.SortFunc(policies, func(a, b policy) int {
-		for _, key := range keys {
-			x := getLow(a, key)
-			y := getLow(b, key)
-			diff := x - y
-			if diff != 0 {
-				return diff
-			}
-		}
-		return 0
-	})
-}
@@ -2,12 +2,10 @@ package aoc2319
 
 import (
 	"slices"
-	"strings"
 	"testing"
 
 	"github.com/denarced/advent-of-code/shared"
 	"github.com/denarced/advent-of-code/shared/inr"
-	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -156,251 +154,6 @@ func TestNegotiate(t *testing.T) {
 	run("in.txt", lines, nil, 167_409_079_868_000)
 }
 
-func TestPolicyIntersection(t *testing.T) {
-	shared.InitTestLogging(t)
-	req := require.New(t)
-
-	first := policy{
-		"a": restriction{low: 2, high: 12},
-		"b": restriction{low: 8, high: 14},
-		"c": restriction{low: 10, high: 12},
-	}
-	second := policy{
-		"a": restriction{low: 1, high: 13},
-		"b": restriction{low: 9, high: 13},
-		"c": restriction{low: 12, high: 14},
-	}
-	// EXERCISE
-	result := first.intersection(second)
-
-	// VERIFY
-	req.Equal(
-		policy{
-			"a": restriction{low: 2, high: 12},
-			"b": restriction{low: 9, high: 13},
-			"c": restriction{low: 12, high: 12},
-		},
-		result)
-}
-
-func TestPierce(t *testing.T) {
-	shared.InitTestLogging(t)
-	req := require.New(t)
-	ass := assert.New(t)
-
-	workflows := map[string]workflow{
-		"in": {
-			name: "in",
-			specs: []spec{
-				{attr: "a", value: 10, dest: "pty"},
-				{dest: "rab", endComplete: true},
-			},
-		},
-		"pty": {
-			name: "pty",
-			specs: []spec{
-				{attr: "b", dest: "A", value: 20, less: true},
-				{dest: "R", endComplete: true},
-			},
-		},
-		"rab": {
-			name: "rab",
-			specs: []spec{
-				{attr: "b", dest: "A", value: 15},
-				{dest: "R", endComplete: true},
-			},
-		},
-	}
-	var actual [][]comparison
-	cb := func(comparisons *shared.Link[comparison]) {
-		var comps []comparison
-		for comparisons != nil {
-			comps = append(comps, comparisons.Item)
-			comparisons = comparisons.Parent
-		}
-		actual = append(actual, comps)
-	}
-
-	// EXERCISE
-	pierce(workflows, "in", nil, cb)
-
-	// VERIFY
-	// #1
-	//     in - a>10
-	//     pty - b<20
-	//     A
-	// #2
-	//     in - a>10
-	//     pty - end
-	//     R
-	// #3  in - a<11
-	//     rab - b>15
-	//     A
-	// #4  in - a<11
-	//     rab - b<16
-	//     R
-	// expected := [][]comparison{
-	// 	{
-	// 		{attr: "b", val: 20, less: true},
-	// 		{attr: "a", val: 10},
-	// 	},
-	// 	{
-	// 		{attr: "b", val: 15},
-	// 	},
-	// }
-	expected := [][]comparison{
-		{
-			{attr: "b", val: 20, less: true},
-			{attr: "a", val: 10},
-		},
-		{
-			{attr: "b", val: 15},
-			{attr: "a", val: 11, less: true},
-		},
-	}
-	req.Equal(expected, actual)
-	if !ass.Equal(expected, actual) {
-		t.Log("Expected")
-		for i, each := range expected {
-			t.Logf("%d %+v", i, each)
-		}
-		t.Log("Actual")
-		for i, each := range actual {
-			t.Logf("%d %+v", i, each)
-		}
-	}
-}
-
-func TestPierceDefect(t *testing.T) {
-	shared.InitTestLogging(t)
-	req := require.New(t)
-
-	var workflows map[string]workflow = map[string]workflow{
-		"in": {
-			name:  "in",
-			specs: []spec{{dest: "qqz", endComplete: true}},
-		},
-		"qqz": {
-			name: "qqz",
-			specs: []spec{
-				{attr: "m", dest: "hdj", value: 4, less: true},
-				{dest: "A", endComplete: true},
-			},
-		},
-		"hdj": {
-			name: "hdj",
-			specs: []spec{
-				{attr: "m", dest: "A", value: 1},
-				{dest: "pv", endComplete: true},
-			},
-		},
-		"pv": {
-			name: "pv",
-			specs: []spec{
-				{attr: "a", dest: "R", value: 2},
-				{dest: "A", endComplete: true},
-			},
-		},
-	}
-	var result [][]comparison
-	// EXERCISE
-	pierce(workflows, "in", nil, func(link *shared.Link[comparison]) {
-		var comparisons []comparison
-		for link != nil {
-			c := link.Item
-			comparisons = append(comparisons, c)
-			link = link.Parent
-		}
-		result = append(result, comparisons)
-	})
-
-	// VERIFY
-	req.Equal(
-		stringifyComparisonSlices([][]comparison{
-			{
-				{attr: "m", val: 1},
-				{attr: "m", val: 4, less: true},
-			},
-			{
-				{attr: "a", val: 3, less: true},
-				{attr: "m", val: 2, less: true},
-				{attr: "m", val: 4, less: true},
-			},
-			{
-				{attr: "m", val: 3, less: false},
-			},
-		}),
-		stringifyComparisonSlices(result))
-}
-
-func stringifyComparisonSlices(comps [][]comparison) []string {
-	result := make([]string, len(comps))
-	for i := range comps {
-		result[i] = stringifyComparisons(comps[i])
-	}
-	return result
-}
-
-func stringifyComparisons(comps []comparison) string {
-	result := make([]string, len(comps))
-	for i := range comps {
-		result[i] = comps[i].String()
-	}
-	return strings.Join(result, ",")
-}
-
-func TestCutRestriction(t *testing.T) {
-	run := func(name string, comp comparison, expectedRest restriction, expectedSuccess bool) {
-		t.Run(name, func(t *testing.T) {
-			shared.InitTestLogging(t)
-			req := require.New(t)
-
-			// EXERCISE
-			result, success := cutRestriction(restriction{low: 10, high: 19}, comp)
-
-			// VERIFY
-			req.Equal(expectedSuccess, success)
-			if expectedSuccess {
-				req.Equal(expectedRest, result)
-			}
-		})
-	}
-
-	run("low-1 - no effect", comparison{val: 8, less: false}, restriction{low: 10, high: 19}, true)
-	run("low - no effect", comparison{val: 9, less: false}, restriction{low: 10, high: 19}, true)
-	run("low+1", comparison{val: 10, less: false}, restriction{low: 11, high: 19}, true)
-	run("high-1", comparison{val: 19, less: true}, restriction{low: 10, high: 18}, true)
-	run("high - no effect", comparison{val: 20, less: true}, restriction{low: 10, high: 19}, true)
-	run("high+1 - no effect", comparison{val: 21, less: true}, restriction{low: 10, high: 19}, true)
-
-	run("high < low", comparison{val: 10, less: true}, restriction{}, false)
-	run("low > high", comparison{val: 19}, restriction{}, false)
-}
-
-func TestDerivePolicy(t *testing.T) {
-	shared.InitTestLogging(t)
-	req := require.New(t)
-
-	link := shared.AddLink(nil, comparison{attr: "a", val: 5, less: false})
-	link = shared.AddLink(link, comparison{attr: "a", val: 15, less: true})
-	// EXERCISE
-	pol := derivePolicy(link, func() policy {
-		return policy{
-			"a": restriction{low: 1, high: 20},
-			"b": restriction{low: 1, high: 20},
-		}
-	})
-
-	// VERIFY
-	req.NotNil(pol)
-	req.Equal(
-		policy{
-			"a": restriction{low: 6, high: 14},
-			"b": restriction{low: 1, high: 20},
-		},
-		pol)
-}
-
 func sumNumbers(lines []string) int {
 	var sum int
 	var on bool
@@ -447,55 +200,3 @@ func TestSumNumbers(t *testing.T) {
 		1+2+4+8+16,
 		sum)
 }
-
-func TestCountLand(t *testing.T) {
-	run := func(name string, policies []policy, expected int) {
-		t.Run(name, func(t *testing.T) {
-			shared.InitTestLogging(t)
-			req := require.New(t)
-
-			// EXERCISE
-			land := countLand(policies)
-
-			// VERIFY
-			req.Equal(expected, land)
-		})
-	}
-
-	rest := func(low, high int) restriction {
-		return restriction{low: low, high: high}
-	}
-	run("nil", nil, 0)
-	run(
-		"one policy",
-		[]policy{{"a": rest(3, 5), "b": rest(10, 12)}},
-		9)
-	run(
-		"full overlap",
-		[]policy{
-			{"a": rest(3, 5), "b": rest(10, 12)},
-			{"a": rest(3, 5), "b": rest(10, 12)},
-		},
-		9)
-	run(
-		"shift by 1 in 1 dimension",
-		[]policy{
-			{"a": rest(3, 5), "b": rest(10, 12)},
-			{"a": rest(4, 6), "b": rest(10, 12)},
-		},
-		12)
-	run(
-		"shift by 1 in 2 dimensions",
-		[]policy{
-			{"a": rest(3, 5), "b": rest(10, 12)},
-			{"a": rest(4, 6), "b": rest(11, 13)},
-		},
-		14)
-	run(
-		"overlap of one cell",
-		[]policy{
-			{"a": rest(1, 3), "b": rest(101, 103)},
-			{"a": rest(3, 5), "b": rest(103, 105)},
-		},
-		17)
-}
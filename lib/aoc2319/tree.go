@@ -0,0 +1,344 @@
+package aoc2319
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/denarced/advent-of-code/shared"
+)
+
+// Range is an inclusive range of attribute values.
+type Range struct {
+	Low, High int
+}
+
+func (v Range) Len() int {
+	return max(0, v.High-v.Low+1)
+}
+
+// Box is a hyper-rectangle of parts: a range for each attribute.
+type Box map[string]Range
+
+// NewBox creates a box with the same range for each attribute.
+func NewBox(low, high int, attrs ...string) Box {
+	box := Box{}
+	for _, each := range attrs {
+		box[each] = Range{Low: low, High: high}
+	}
+	return box
+}
+
+// Count counts the parts in the box.
+func (v Box) Count() int {
+	if len(v) == 0 {
+		return 0
+	}
+	count := 1
+	for _, each := range v {
+		count *= each.Len()
+	}
+	return count
+}
+
+func (v Box) String() string {
+	keys := slices.Sorted(maps.Keys(v))
+	pieces := make([]string, len(keys))
+	for i, each := range keys {
+		pieces[i] = fmt.Sprintf("%s=%d..%d", each, v[each].Low, v[each].High)
+	}
+	return "{" + strings.Join(pieces, " ") + "}"
+}
+
+func (v Box) with(attr string, rng Range) Box {
+	copied := maps.Clone(v)
+	copied[attr] = rng
+	return copied
+}
+
+// Rule is a rule of a workflow.
+type Rule struct {
+	Workflow string
+	Index    int
+	Text     string
+}
+
+func (v Rule) String() string {
+	return fmt.Sprintf("%s[%d] %s", v.Workflow, v.Index, v.Text)
+}
+
+// decision is a node of the tree. Rules are nodes whose pass branch is taken when the comparison
+// holds, or always when the rule has no comparison. Leaves accept or reject.
+type decision struct {
+	rule       Rule
+	comparison *comparison
+	pass, fail *decision
+	leaf       bool
+	accept     bool
+}
+
+// split splits the box to the part that passes the rule and the part that fails it. Empty parts
+// are nil.
+func (v *decision) split(box Box) (pass, fail Box) {
+	if v.comparison == nil {
+		return box, nil
+	}
+	rng, ok := box[v.comparison.attr]
+	if !ok {
+		panic(fmt.Sprintf("Box %s has no attribute %s.", box, v.comparison.attr))
+	}
+	passing, failing := rng, rng
+	if v.comparison.less {
+		passing.High = min(rng.High, v.comparison.val-1)
+		failing.Low = max(rng.Low, v.comparison.val)
+	} else {
+		passing.Low = max(rng.Low, v.comparison.val+1)
+		failing.High = min(rng.High, v.comparison.val)
+	}
+	if passing.Len() > 0 {
+		pass = box.with(v.comparison.attr, passing)
+	}
+	if failing.Len() > 0 {
+		fail = box.with(v.comparison.attr, failing)
+	}
+	return
+}
+
+func (v *decision) label() string {
+	if v.leaf {
+		return shared.Or(v.accept, "A", "R")
+	}
+	if v.comparison == nil {
+		return fmt.Sprintf("%s[%d]", v.rule.Workflow, v.rule.Index)
+	}
+	return fmt.Sprintf("%s[%d] %s", v.rule.Workflow, v.rule.Index, v.comparison)
+}
+
+// Tree is the workflows compiled into a decision tree. Workflows that are sent to from several
+// rules are shared so it's really a DAG.
+type Tree struct {
+	root *decision
+	// Workflow names in the order they're defined.
+	names []string
+	roots map[string]*decision
+	rules []Rule
+}
+
+var (
+	acceptLeaf = &decision{leaf: true, accept: true}
+	rejectLeaf = &decision{leaf: true}
+)
+
+// Compile compiles the workflows of the lines. Parts after the workflows are ignored.
+func Compile(lines []string) (*Tree, error) {
+	blocks := shared.SplitToBlocks(lines)
+	if len(blocks) == 0 {
+		return nil, errors.New("no workflows")
+	}
+	workflows := map[string]workflow{}
+	tree := &Tree{roots: map[string]*decision{}}
+	for _, each := range blocks[0] {
+		flow := parseWorkflow(each)
+		if _, ok := workflows[flow.name]; ok {
+			return nil, fmt.Errorf("workflow %s is defined twice", flow.name)
+		}
+		workflows[flow.name] = flow
+		tree.names = append(tree.names, flow.name)
+	}
+	if _, ok := workflows["in"]; !ok {
+		return nil, errors.New("no in workflow")
+	}
+	compiler := treeCompiler{workflows: workflows, tree: tree}
+	for _, each := range tree.names {
+		if _, err := compiler.compile(each); err != nil {
+			return nil, err
+		}
+	}
+	tree.root = tree.roots["in"]
+	shared.Logger.Info("Workflows compiled.", "workflow count", len(tree.names))
+	return tree, nil
+}
+
+type treeCompiler struct {
+	workflows map[string]workflow
+	tree      *Tree
+	// Workflows being compiled, to detect loops.
+	stack []string
+}
+
+func (v *treeCompiler) compile(name string) (*decision, error) {
+	if root, ok := v.tree.roots[name]; ok {
+		return root, nil
+	}
+	if index := slices.Index(v.stack, name); index >= 0 {
+		return nil, fmt.Errorf(
+			"workflow loop: %s",
+			strings.Join(append(slices.Clone(v.stack[index:]), name), " -> "))
+	}
+	v.stack = append(v.stack, name)
+	defer func() { v.stack = v.stack[:len(v.stack)-1] }()
+
+	flow := v.workflows[name]
+	nodes := make([]*decision, len(flow.specs))
+	for i, each := range flow.specs {
+		text := each.dest
+		nod := &decision{}
+		if !each.endComplete {
+			nod.comparison = &comparison{attr: each.attr, val: each.value, less: each.less}
+			text = fmt.Sprintf("%s:%s", nod.comparison, each.dest)
+		}
+		nod.rule = Rule{Workflow: name, Index: i, Text: text}
+		nodes[i] = nod
+	}
+	for i, each := range flow.specs {
+		pass, err := v.target(name, each.dest)
+		if err != nil {
+			return nil, err
+		}
+		nodes[i].pass = pass
+		if i+1 < len(nodes) {
+			nodes[i].fail = nodes[i+1]
+		} else if !each.endComplete {
+			// A workflow that ends with a comparison rejects the rest, at least in this tree.
+			nodes[i].fail = rejectLeaf
+		}
+	}
+	for _, each := range nodes {
+		v.tree.rules = append(v.tree.rules, each.rule)
+	}
+	v.tree.roots[name] = nodes[0]
+	return nodes[0], nil
+}
+
+func (v *treeCompiler) target(from, dest string) (*decision, error) {
+	switch dest {
+	case "A":
+		return acceptLeaf, nil
+	case "R":
+		return rejectLeaf, nil
+	}
+	if _, ok := v.workflows[dest]; !ok {
+		return nil, fmt.Errorf("unknown workflow %s in %s", dest, from)
+	}
+	return v.compile(dest)
+}
+
+// Classify checks whether the part is accepted.
+func (v *Tree) Classify(aPart map[string]int) bool {
+	nod := v.root
+	for !nod.leaf {
+		if nod.comparison == nil {
+			nod = nod.pass
+			continue
+		}
+		value := part(aPart).pick(nod.comparison.attr)
+		if shared.Or(nod.comparison.less, value < nod.comparison.val, value > nod.comparison.val) {
+			nod = nod.pass
+		} else {
+			nod = nod.fail
+		}
+	}
+	return nod.accept
+}
+
+// walk calls cb for every node that parts in box reach, with the parts that reach it.
+func walk(nod *decision, box Box, cb func(nod *decision, box Box)) {
+	cb(nod, box)
+	if nod.leaf {
+		return
+	}
+	pass, fail := nod.split(box)
+	if pass != nil {
+		walk(nod.pass, pass, cb)
+	}
+	if fail != nil {
+		walk(nod.fail, fail, cb)
+	}
+}
+
+// Accepted lists the boxes of accepted parts within bounds. The boxes don't overlap.
+func (v *Tree) Accepted(bounds Box) []Box {
+	var boxes []Box
+	walk(v.root, bounds, func(nod *decision, box Box) {
+		if nod.leaf && nod.accept {
+			boxes = append(boxes, box)
+		}
+	})
+	return boxes
+}
+
+// Count counts the accepted parts within bounds.
+func (v *Tree) Count(bounds Box) int {
+	return count(v.root, bounds)
+}
+
+func count(root *decision, bounds Box) int {
+	var total int
+	walk(root, bounds, func(nod *decision, box Box) {
+		if nod.leaf && nod.accept {
+			total += box.Count()
+		}
+	})
+	return total
+}
+
+// Unreachable lists the rules that no part within bounds reaches. Rules of workflows that in never
+// sends to are all unreachable.
+func (v *Tree) Unreachable(bounds Box) []Rule {
+	reached := map[Rule]bool{}
+	walk(v.root, bounds, func(nod *decision, _ Box) {
+		if !nod.leaf {
+			reached[nod.rule] = true
+		}
+	})
+	var rules []Rule
+	for _, each := range v.rules {
+		if !reached[each] {
+			rules = append(rules, each)
+		}
+	}
+	slices.SortStableFunc(rules, func(a, b Rule) int {
+		return slices.Index(v.names, a.Workflow) - slices.Index(v.names, b.Workflow)
+	})
+	return rules
+}
+
+// Dead lists the workflows that don't accept any part within bounds.
+func (v *Tree) Dead(bounds Box) []string {
+	var dead []string
+	for _, each := range v.names {
+		if count(v.roots[each], bounds) == 0 {
+			dead = append(dead, each)
+		}
+	}
+	return dead
+}
+
+// Dump dumps the tree as text. A node that has already been dumped isn't expanded again.
+func (v *Tree) Dump() string {
+	var b strings.Builder
+	dumped := map[*decision]bool{}
+	var dump func(nod *decision, prefix, childPrefix string)
+	dump = func(nod *decision, prefix, childPrefix string) {
+		b.WriteString(prefix + nod.label())
+		if !nod.leaf && dumped[nod] {
+			b.WriteString(" (see above)\n")
+			return
+		}
+		b.WriteString("\n")
+		dumped[nod] = true
+		if nod.leaf {
+			return
+		}
+		if nod.comparison == nil {
+			dump(nod.pass, childPrefix+"└── ", childPrefix+"    ")
+			return
+		}
+		dump(nod.pass, childPrefix+"├── yes: ", childPrefix+"│   ")
+		dump(nod.fail, childPrefix+"└── no: ", childPrefix+"    ")
+	}
+	dump(v.root, "", "")
+	return b.String()
+}
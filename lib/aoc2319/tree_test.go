@@ -0,0 +1,165 @@
+package aoc2319
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/inr"
+	"github.com/denarced/gent"
+	"github.com/stretchr/testify/require"
+)
+
+var smallWorkflows = []string{
+	"in{x<5:low,m>7:A,high}",
+	"low{a<3:R,s>2:A,x>2:R,A}",
+	"high{s<9:A,x>8:R,a<2:A,R}",
+	"none{R}",
+}
+
+func compileTestData() *Tree {
+	lines := gent.OrPanic2(inr.ReadPath("testdata/in.txt", inr.IncludeEmpty()))("read test data")
+	return gent.OrPanic2(Compile(lines))("compile")
+}
+
+func TestCompileErrors(t *testing.T) {
+	run := func(name string, lines []string, expected string) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+
+			// EXERCISE
+			_, err := Compile(lines)
+
+			// VERIFY
+			require.EqualError(t, err, expected)
+		})
+	}
+
+	run("empty", nil, "no workflows")
+	run("no in", []string{"a{A}"}, "no in workflow")
+	run("twice", []string{"in{A}", "in{R}"}, "workflow in is defined twice")
+	run("unknown", []string{"in{x<2:a,A}"}, "unknown workflow a in in")
+	run(
+		"loop",
+		[]string{"in{a}", "a{x<3:b,A}", "b{m>2:a,R}"},
+		"workflow loop: a -> b -> a")
+}
+
+func TestClassify(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	tree := compileTestData()
+
+	// EXERCISE & VERIFY
+	req.True(tree.Classify(map[string]int{"x": 787, "m": 2655, "a": 1222, "s": 2876}))
+	req.False(tree.Classify(map[string]int{"x": 1679, "m": 44, "a": 2067, "s": 496}))
+	req.True(tree.Classify(map[string]int{"x": 2036, "m": 264, "a": 79, "s": 2244}))
+	req.False(tree.Classify(map[string]int{"x": 2461, "m": 1339, "a": 466, "s": 291}))
+	req.True(tree.Classify(map[string]int{"x": 2127, "m": 1623, "a": 2188, "s": 1013}))
+}
+
+func TestCount(t *testing.T) {
+	run := func(bounds Box) {
+		t.Run(bounds.String(), func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			tree := gent.OrPanic2(Compile(smallWorkflows))("compile")
+			var expected int
+			for x := bounds["x"].Low; x <= bounds["x"].High; x++ {
+				for m := bounds["m"].Low; m <= bounds["m"].High; m++ {
+					for a := bounds["a"].Low; a <= bounds["a"].High; a++ {
+						for s := bounds["s"].Low; s <= bounds["s"].High; s++ {
+							if tree.Classify(map[string]int{"x": x, "m": m, "a": a, "s": s}) {
+								expected++
+							}
+						}
+					}
+				}
+			}
+
+			// EXERCISE
+			actual := tree.Count(bounds)
+
+			// VERIFY
+			req.Equal(expected, actual)
+			var sum int
+			for _, each := range tree.Accepted(bounds) {
+				sum += each.Count()
+			}
+			req.Equal(expected, sum)
+		})
+	}
+
+	run(NewBox(1, 10, "x", "m", "a", "s"))
+	run(NewBox(3, 8, "x", "m", "a", "s"))
+	run(Box{"x": {1, 4}, "m": {8, 12}, "a": {0, 3}, "s": {5, 5}})
+	run(Box{"x": {6, 12}, "m": {1, 7}, "a": {1, 1}, "s": {9, 15}})
+}
+
+func TestAccepted(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	tree := gent.OrPanic2(Compile(smallWorkflows))("compile")
+
+	// EXERCISE
+	boxes := tree.Accepted(Box{"x": {1, 10}, "m": {1, 10}, "a": {1, 10}, "s": {1, 10}})
+
+	// VERIFY
+	req.Equal(
+		[]string{
+			"{a=3..10 m=1..10 s=3..10 x=1..4}",
+			"{a=3..10 m=1..10 s=1..2 x=1..2}",
+			"{a=1..10 m=8..10 s=1..10 x=5..10}",
+			"{a=1..10 m=1..7 s=1..8 x=5..10}",
+			"{a=1..1 m=1..7 s=9..10 x=5..8}",
+		},
+		gent.Map(boxes, Box.String))
+}
+
+func TestUnreachable(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	tree := gent.OrPanic2(Compile(smallWorkflows))("compile")
+
+	// EXERCISE & VERIFY
+	req.Equal(
+		[]string{"none[0] R"},
+		gent.Map(tree.Unreachable(NewBox(1, 10, "x", "m", "a", "s")), Rule.String))
+	req.Equal(
+		[]string{"low[2] x>2:R", "low[3] A", "none[0] R"},
+		gent.Map(tree.Unreachable(NewBox(3, 10, "x", "m", "a", "s")), Rule.String))
+	req.Empty(compileTestData().Unreachable(NewBox(1, 4000, "x", "m", "a", "s")))
+}
+
+func TestDead(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+
+	// EXERCISE & VERIFY
+	req.Equal([]string{"gd"}, compileTestData().Dead(NewBox(1, 4000, "x", "m", "a", "s")))
+	tree := gent.OrPanic2(Compile(smallWorkflows))("compile")
+	req.Equal([]string{"none"}, tree.Dead(NewBox(1, 10, "x", "m", "a", "s")))
+	req.Equal([]string{"in", "low", "none"}, tree.Dead(NewBox(1, 2, "x", "m", "a", "s")))
+}
+
+func TestDump(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	tree := gent.OrPanic2(Compile([]string{"in{x<5:a,m>7:A,a}", "a{s<3:R,A}"}))("compile")
+
+	// EXERCISE & VERIFY
+	req.Equal(
+		strings.Join([]string{
+			"in[0] x<5",
+			"├── yes: a[0] s<3",
+			"│   ├── yes: R",
+			"│   └── no: a[1]",
+			"│       └── A",
+			"└── no: in[1] m>7",
+			"    ├── yes: A",
+			"    └── no: in[2]",
+			"        └── a[0] s<3 (see above)",
+			"",
+		}, "\n"),
+		tree.Dump())
+}
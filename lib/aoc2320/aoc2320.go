@@ -1,10 +1,11 @@
 package aoc2320
 
 import (
-	"sort"
+	"fmt"
 	"strings"
 
 	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/graph"
 	"github.com/denarced/gent"
 )
 
@@ -29,60 +30,27 @@ type FiringSquad struct {
 	RoundCb          func(int, map[string]*Processor) bool
 	processors       map[string]*Processor
 	ComponentCallers map[string][]string
+	// Module names in the order they're defined.
+	names []string
 }
 
 func NewFiringSquad(lines []string) *FiringSquad {
 	shared.Logger.Info("Create FiringSquad.", "line count", len(lines))
-	processors, componentsToCallers := parseLines(lines)
+	processors, componentsToCallers, names := parseLines(lines)
 	shared.Logger.Info("Lines parsed.", "processor count", len(processors))
-	return &FiringSquad{processors: processors, ComponentCallers: componentsToCallers}
+	return &FiringSquad{
+		processors:       processors,
+		ComponentCallers: componentsToCallers,
+		names:            names,
+	}
 }
 
 func (v *FiringSquad) Fire() {
 	shared.Logger.Info("Fire.", "monitor processors", v.RoundCb != nil)
-	raysetLength := len(v.processors)
-	raysets := make([]rayset, raysetLength)
+	raysets := make([]rayset, len(v.processors))
 	var i int
 	for i = 0; ; i++ {
-		// It's a bit silly but use a circular buffer to dramatically reduce allocations during
-		// execution. Compared to simple deque (pop from beginning, append to end) benchmark
-		// improved from 265µs to 144µs (-46%). Allocations dropped from 7k to 1k and memory usage
-		// from 370KiB to 18KiB. That's with puzzle example so with real input the numbers would be
-		// even more dramatic. Duration with real input: 10ms to 3ms. I.e. the optimization is
-		// pointless but it was fun to try so why not.
-		raysets[0] = rayset{name: "button", pulse: Low, targets: []string{initName}}
-		rayCount := 1
-		popIndex := 0
-		pushIndex := popIndex + rayCount
-		var halt bool
-		for rayCount > 0 {
-			nextPopIndex := pushIndex
-			var nextRayCount int
-			for range rayCount {
-				rs := raysets[popIndex%raysetLength]
-				popIndex++
-				for _, target := range rs.targets {
-					if v.SignalCb != nil {
-						v.SignalCb(rs.pulse)
-					}
-					if processor := v.processors[target]; processor != nil {
-						if next := processor.process(rs.name, rs.pulse); len(next.targets) > 0 {
-							raysets[pushIndex%raysetLength] = next
-							pushIndex++
-							nextRayCount++
-						}
-					}
-				}
-			}
-			if v.RoundCb != nil && !v.RoundCb(i+1, v.processors) {
-				shared.Logger.Info("RoundCb asked to halt.")
-				halt = true
-				break
-			}
-			popIndex = nextPopIndex
-			rayCount = nextRayCount
-			pushIndex = popIndex + rayCount
-		}
+		halt := v.press(raysets, i+1, nil)
 		if v.RoundCb == nil && i >= 999 {
 			break
 		}
@@ -94,6 +62,69 @@ func (v *FiringSquad) Fire() {
 	shared.Logger.Info("Fired done.", "button presses", i+1)
 }
 
+// Press presses the button once and returns every pulse sent, in order.
+func (v *FiringSquad) Press() []Signal {
+	var trace []Signal
+	v.press(make([]rayset, len(v.processors)), 1, &trace)
+	shared.Logger.Debug("Button pressed.", "pulse count", len(trace))
+	return trace
+}
+
+// press presses the button once. Pulses are appended to trace when it's not nil.
+func (v *FiringSquad) press(raysets []rayset, clickCount int, trace *[]Signal) (halt bool) {
+	// It's a bit silly but use a circular buffer to dramatically reduce allocations during
+	// execution. Compared to simple deque (pop from beginning, append to end) benchmark
+	// improved from 265µs to 144µs (-46%). Allocations dropped from 7k to 1k and memory usage
+	// from 370KiB to 18KiB. That's with puzzle example so with real input the numbers would be
+	// even more dramatic. Duration with real input: 10ms to 3ms. I.e. the optimization is
+	// pointless but it was fun to try so why not.
+	raysetLength := len(raysets)
+	raysets[0] = rayset{name: "button", pulse: Low, targets: []string{initName}}
+	rayCount := 1
+	popIndex := 0
+	pushIndex := popIndex + rayCount
+	for rayCount > 0 {
+		nextPopIndex := pushIndex
+		var nextRayCount int
+		for range rayCount {
+			rs := raysets[popIndex%raysetLength]
+			popIndex++
+			for _, target := range rs.targets {
+				if v.SignalCb != nil {
+					v.SignalCb(rs.pulse)
+				}
+				if trace != nil {
+					*trace = append(*trace, Signal{From: rs.name, To: target, Pulse: rs.pulse})
+				}
+				if processor := v.processors[target]; processor != nil {
+					if next := processor.process(rs.name, rs.pulse); len(next.targets) > 0 {
+						raysets[pushIndex%raysetLength] = next
+						pushIndex++
+						nextRayCount++
+					}
+				}
+			}
+		}
+		if v.RoundCb != nil && !v.RoundCb(clickCount, v.processors) {
+			shared.Logger.Info("RoundCb asked to halt.")
+			return true
+		}
+		popIndex = nextPopIndex
+		rayCount = nextRayCount
+		pushIndex = popIndex + rayCount
+	}
+	return false
+}
+
+// Modules returns the modules in the order they're defined.
+func (v *FiringSquad) Modules() []Module {
+	modules := make([]Module, 0, len(v.names))
+	for _, each := range v.names {
+		modules = append(modules, v.processors[each].Module)
+	}
+	return modules
+}
+
 func (v Pulse) String() string {
 	if v == Low {
 		return "low"
@@ -101,6 +132,17 @@ func (v Pulse) String() string {
 	return "high"
 }
 
+// Signal is a pulse sent from one module to another.
+type Signal struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Pulse Pulse  `json:"pulse"`
+}
+
+func (v Signal) String() string {
+	return fmt.Sprintf("%s -%s-> %s", v.From, v.Pulse, v.To)
+}
+
 type rayset struct {
 	// Name of the sender.
 	name  string
@@ -109,130 +151,117 @@ type rayset struct {
 	targets []string
 }
 
+type Kind int
+
+const (
+	Broadcaster Kind = iota
+	FlipFlop
+	Conjunction
+)
+
+func (v Kind) String() string {
+	switch v {
+	case Broadcaster:
+		return "broadcaster"
+	case FlipFlop:
+		return "flip-flop"
+	case Conjunction:
+		return "conjunction"
+	}
+	panic(fmt.Sprintf("Unknown kind: %d.", int(v)))
+}
+
+// Module is the wiring of a module.
+type Module struct {
+	Name    string
+	Kind    Kind
+	Inputs  []string
+	Targets []string
+}
+
+// Processor is a module and its state.
 type Processor struct {
-	All     func(Pulse) bool
-	process func(string, Pulse) rayset
+	Module
+	// On is the state of a flip-flop.
+	On bool
+	// Memory is the last pulse received from each input of a conjunction.
+	Memory map[string]Pulse
 }
 
-func parseLines(lines []string) (map[string]*Processor, map[string][]string) {
-	components := map[string]*Processor{}
-	conjunctionRegistrars := map[string]func([]string){}
-	componentsToCallers := map[string][]string{}
-	addComponent := func(component, caller string) {
-		callers := componentsToCallers[component]
-		if callers == nil {
-			callers = []string{}
-		}
-		callers = append(callers, caller)
-		componentsToCallers[component] = callers
-	}
-	for _, each := range lines {
-		pieces := gent.Map(strings.Split(each, "->"), strings.TrimSpace)
-		targets := gent.Map(strings.Split(pieces[1], ","), strings.TrimSpace)
-		if pieces[0] == initName {
-			components[pieces[0]] = &Processor{
-				process: func(_ string, pul Pulse) rayset {
-					return rayset{
-						name:    pieces[0],
-						pulse:   pul,
-						targets: targets,
-					}
-				},
-			}
-			for _, target := range targets {
-				addComponent(target, pieces[0])
+// All checks whether the processor's state is pulse: all remembered inputs of a conjunction are
+// pulse or a flip-flop is on for high and off for low. The broadcaster has no state.
+func (v *Processor) All(pulse Pulse) bool {
+	switch v.Kind {
+	case FlipFlop:
+		return v.On == (pulse == High)
+	case Conjunction:
+		for _, each := range v.Memory {
+			if each != pulse {
+				return false
 			}
-			continue
-		}
-		prefix, name := pieces[0][0], pieces[0][1:]
-		for _, target := range targets {
-			addComponent(target, name)
 		}
-		switch prefix {
-		case '%':
-			components[name] = createFlipFlop(name, targets)
-		case '&':
-			var reg func([]string)
-			components[name], reg = createConjunction(name, targets)
-			conjunctionRegistrars[name] = reg
-		default:
-			panic("unknown type: " + string(prefix))
-		}
-	}
-	for name, registrar := range conjunctionRegistrars {
-		registrar(componentsToCallers[name])
+		return true
 	}
-	return components, componentsToCallers
+	return false
 }
 
-func createFlipFlop(name string, targets []string) *Processor {
-	on := false
-	return &Processor{
-		process: func(_ string, pul Pulse) (ray rayset) {
-			ray.name = name
-			if pul == High {
-				return
-			}
-			ray.targets = targets
-			ray.pulse = gent.Tri(on, Low, High)
-			on = !on
+func (v *Processor) process(shooter string, pul Pulse) (ray rayset) {
+	ray.name = v.Name
+	switch v.Kind {
+	case Broadcaster:
+		ray.pulse = pul
+	case FlipFlop:
+		if pul == High {
 			return
-		},
-		All: func(_ Pulse) bool {
-			panic("no one should ever call flip-flop All")
-		},
+		}
+		ray.pulse = gent.Tri(v.On, Low, High)
+		v.On = !v.On
+	case Conjunction:
+		v.Memory[shooter] = pul
+		ray.pulse = gent.Tri(v.All(High), Low, High)
 	}
+	ray.targets = v.Targets
+	return
 }
 
-func createConjunction(name string, targets []string) (*Processor, func([]string)) {
-	var shooters map[string]Pulse
-	var keys []string
-	proc := Processor{
-		process: func(shooter string, pul Pulse) (ray rayset) {
-			shooters[shooter] = pul
-			ray.name = name
-			ray.targets = targets
-			for _, each := range shooters {
-				if each == Low {
-					ray.pulse = High
-					return
-				}
-			}
-			return
-		},
-		All: func(pulse Pulse) bool {
-			for _, each := range shooters {
-				if each != pulse {
-					return false
-				}
+func parseLines(lines []string) (map[string]*Processor, map[string][]string, []string) {
+	components := map[string]*Processor{}
+	componentsToCallers := map[string][]string{}
+	var names []string
+	for _, each := range lines {
+		if strings.TrimSpace(each) == "" {
+			continue
+		}
+		pieces := gent.Map(strings.Split(each, "->"), strings.TrimSpace)
+		targets := gent.Map(strings.Split(pieces[1], ","), strings.TrimSpace)
+		name, kind := pieces[0], Broadcaster
+		if name != initName {
+			switch name[0] {
+			case '%':
+				kind = FlipFlop
+			case '&':
+				kind = Conjunction
+			default:
+				panic("unknown type: " + string(name[0]))
 			}
-			return true
-		},
-	}
-	return &proc, func(shooterNames []string) {
-		shooters = map[string]Pulse{}
-		keys = []string{}
-		for _, each := range shooterNames {
-			shooters[each] = Low
-			keys = append(keys, each)
+			name = name[1:]
 		}
-		sort.Strings(keys)
-	}
-}
-
-func FindTracked(components map[string][]string, name string, pulse Pulse) ([]string, Pulse) {
-	for {
-		callers := components[name]
-		if len(callers) == 0 {
-			return nil, Low
+		for _, target := range targets {
+			componentsToCallers[target] = append(componentsToCallers[target], name)
 		}
-		pulse = pulse.Flip()
-		if len(callers) == 1 {
-			name = callers[0]
-			continue
+		components[name] = &Processor{Module: Module{Name: name, Kind: kind, Targets: targets}}
+		names = append(names, name)
+	}
+	for name, processor := range components {
+		processor.Inputs = componentsToCallers[name]
+		if processor.Kind == Conjunction {
+			processor.Memory = map[string]Pulse{}
+			for _, each := range processor.Inputs {
+				processor.Memory[each] = Low
+			}
 		}
-		return callers, pulse
 	}
+	return components, componentsToCallers, names
 }
 
 type SignalTracker struct {
@@ -248,84 +277,22 @@ func (v *SignalTracker) Add(pulse Pulse) {
 	}
 }
 
-type RoundMonitor struct {
-	components         []string
-	expectedPulse      Pulse
-	counts             map[string][]int
-	resolvedComponents *gent.Set[string]
-	Frequencies        []int
-}
-
-func NewRoundMonitor(components []string, expectedPulse Pulse) *RoundMonitor {
-	return &RoundMonitor{
-		components:         components,
-		expectedPulse:      expectedPulse,
-		counts:             make(map[string][]int, len(components)),
-		resolvedComponents: gent.NewSet[string](),
-		Frequencies:        make([]int, len(components)),
-	}
-}
-
-func (v *RoundMonitor) Monitor(clickCount int, processors map[string]*Processor) bool {
-	// Component could be initialized to the expected state but that's ignored because such an
-	// obvious accident clearly isn't the sought answer.
-	if clickCount <= 1 {
-		return true
+// DrawModules creates a diagram of the modules. Modules that only receive pulses, such as rx, are
+// outputs.
+func DrawModules(lines []string) *graph.Diagram {
+	shared.Logger.Info("Draw modules.", "line count", len(lines))
+	squad := NewFiringSquad(lines)
+	diagram := graph.NewDiagram(true)
+	for _, each := range squad.Modules() {
+		diagram.AddNode(each.Name, each.Kind.String())
 	}
-	for i, each := range v.components {
-		if v.resolvedComponents.Has(each) {
-			continue
-		}
-		if !processors[each].All(v.expectedPulse) {
-			continue
-		}
-		last, ok := getLast(v.counts[each])
-		if ok && last == clickCount {
-			continue
-		}
-		v.counts[each] = append(v.counts[each], clickCount)
-		if len(v.counts[each]) > 10 {
-			freq, ok := resolveFrequency(v.counts[each])
-			if ok {
-				v.Frequencies[i] = freq
-				v.resolvedComponents.Add(each)
-				shared.Logger.Info(
-					"Component frequency found.",
-					"name", each,
-					"frequency", freq)
+	for _, each := range squad.Modules() {
+		for _, target := range each.Targets {
+			if squad.processors[target] == nil {
+				diagram.AddNode(target, "output")
 			}
+			diagram.AddEdge(each.Name, target, "")
 		}
 	}
-	if v.resolvedComponents.Count() >= len(v.components) {
-		shared.Logger.Info("All component frequencies found, quitting.")
-		return false
-	}
-	return true
-}
-
-func getLast(values []int) (int, bool) {
-	if len(values) == 0 {
-		return 0, false
-	}
-	return values[len(values)-1], true
-}
-
-func resolveFrequency(values []int) (int, bool) {
-	size := len(values)
-	if size < 10 {
-		return 0, false
-	}
-	last := -1
-	for i := size - 1; i >= size-5; i-- {
-		prev, next := values[i-1], values[i]
-		freq := next - prev
-		if last < 0 {
-			last = freq
-			continue
-		}
-		if freq != last {
-			return 0, false
-		}
-	}
-	return last, true
+	return diagram
 }
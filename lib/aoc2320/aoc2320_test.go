@@ -46,16 +46,19 @@ func BenchmarkFire(b *testing.B) {
 	}
 }
 
-func TestFindTracked(t *testing.T) {
+func TestDrawModules(t *testing.T) {
+	shared.InitTestLogging(t)
 	req := require.New(t)
+	lines, err := inr.ReadPath(filepath.Join("testdata", "in2.txt"))
+	req.NoError(err)
 
 	// EXERCISE
-	components := map[string][]string{
-		"one": {"two"},
-		"two": {"three", "four"},
-	}
-	callers, soughtPulse := FindTracked(components, "one", Low)
+	mermaid := DrawModules(lines).Mermaid()
 
-	req.Equal([]string{"three", "four"}, callers)
-	req.Equal(Low, soughtPulse)
+	// VERIFY
+	req.Contains(mermaid, `n0(["broadcaster (broadcaster)"])`)
+	req.Contains(mermaid, `n1["a (flip-flop)"]`)
+	req.Contains(mermaid, `n2{"inv (conjunction)"}`)
+	req.Contains(mermaid, `n5{{"output (output)"}}`)
+	req.Contains(mermaid, "n1 --> n4")
 }
@@ -0,0 +1,243 @@
+package aoc2320
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/graph"
+)
+
+// Counter is a binary counter of flip-flops. Its hub conjunction remembers some of the bits. When
+// they're all on, the hub sends low to the rest of the bits and to bit 0, which resets the counter,
+// and towards the final conjunction.
+type Counter struct {
+	Hub string
+	// Bits are the flip-flops from the least significant.
+	Bits []string
+	// Value is what the counter is at now, before the next press.
+	Value int
+	// The counter fires for the first time on press First, counted from now. The reset leaves on
+	// the bits that were on but aren't in Period so the second firing can come sooner than Period
+	// presses later. From then on the counter fires when the press is Phase modulo Period.
+	Period int
+	Phase  int
+	First  int
+}
+
+// firesOn checks whether the counter fires on the press.
+func (v Counter) firesOn(press int) bool {
+	return press == v.First || press > v.First && press%v.Period == v.Phase
+}
+
+// Activation explains when the output receives a low pulse: the final conjunction, that sends to
+// the output, receives high from every counter on the same press. Presses are counted from the
+// current state.
+type Activation struct {
+	Final    string
+	Counters []Counter
+	Presses  int
+}
+
+// AnalyzeActivation finds the first button press on which output receives a low pulse by reading
+// the wiring: the network has to consist of independent binary counters that feed the final
+// conjunction. The error explains which part of the structure is missing.
+func (v *FiringSquad) AnalyzeActivation(output string) (Activation, error) {
+	shared.Logger.Info("Analyze activation.", "output", output)
+	callers := v.ComponentCallers[output]
+	if len(callers) != 1 {
+		return Activation{}, fmt.Errorf("%s should have one caller, it has %d", output, len(callers))
+	}
+	final := v.processors[callers[0]]
+	if final.Kind != Conjunction {
+		return Activation{}, fmt.Errorf("%s, the caller of %s, isn't a conjunction", final.Name, output)
+	}
+	components := v.findComponents()
+	activation := Activation{Final: final.Name}
+	combined := shared.Congruence{Remainder: 0, Modulus: 1}
+	var latest int
+	for _, each := range final.Inputs {
+		hub, err := v.findHub(each)
+		if err != nil {
+			return Activation{}, err
+		}
+		counter, err := v.readCounter(hub, components[hub.Name])
+		if err != nil {
+			return Activation{}, fmt.Errorf("counter of %s: %w", hub.Name, err)
+		}
+		shared.Logger.Info("Counter found.", "counter", counter)
+		activation.Counters = append(activation.Counters, counter)
+		latest = max(latest, counter.First)
+		var ok bool
+		combined, ok = shared.CombineCongruences(
+			combined,
+			shared.Congruence{Remainder: counter.Phase, Modulus: counter.Period})
+		if !ok {
+			return Activation{}, fmt.Errorf("counters never fire on the same press: %v",
+				activation.Counters)
+		}
+	}
+	// Every counter has to have fired once before the congruences hold.
+	activation.Presses = combined.Remainder
+	if activation.Presses < latest {
+		activation.Presses += (latest - activation.Presses + combined.Modulus - 1) /
+			combined.Modulus * combined.Modulus
+	}
+	// The first firings are outside of the congruences so they might coincide sooner.
+	for _, each := range activation.Counters {
+		if each.First < activation.Presses &&
+			!slices.ContainsFunc(activation.Counters, func(c Counter) bool {
+				return !c.firesOn(each.First)
+			}) {
+			activation.Presses = each.First
+		}
+	}
+	shared.Logger.Info("Activation analyzed.", "presses", activation.Presses)
+	return activation, nil
+}
+
+// findComponents maps each module to the other modules of its strongly connected component.
+func (v *FiringSquad) findComponents() map[string][]string {
+	var edges []shared.Pair[string]
+	for _, each := range v.Modules() {
+		for _, target := range each.Targets {
+			edges = append(edges, shared.NewPair(each.Name, target))
+		}
+	}
+	components := map[string][]string{}
+	for _, component := range graph.NewDirected(edges).StronglyConnectedComponents() {
+		for _, each := range component {
+			components[each] = component
+		}
+	}
+	return components
+}
+
+// findHub walks back from an input of the final conjunction to the hub of a counter. The input has
+// to receive high when the hub sends low so there has to be an odd number of inverters, single
+// input conjunctions, on the way.
+func (v *FiringSquad) findHub(input string) (*Processor, error) {
+	current := v.processors[input]
+	var inverters int
+	for current.Kind == Conjunction && len(current.Inputs) == 1 {
+		inverters++
+		current = v.processors[current.Inputs[0]]
+		if current == nil {
+			return nil, fmt.Errorf("%s isn't fed by a counter", input)
+		}
+	}
+	if current.Kind != Conjunction {
+		return nil, fmt.Errorf("%s isn't fed by a counter, found %s %s",
+			input,
+			current.Kind,
+			current.Name)
+	}
+	if inverters%2 == 0 {
+		return nil, fmt.Errorf("%s receives low when counter %s fires", input, current.Name)
+	}
+	return current, nil
+}
+
+// readCounter reads the counter around hub and checks that it really is a binary counter: the
+// broadcaster starts a chain of flip-flops that covers the rest of the component. Bit 0 and the
+// last bit feed the hub and every other bit either feeds it or is reset by it.
+func (v *FiringSquad) readCounter(hub *Processor, component []string) (Counter, error) {
+	counter := Counter{Hub: hub.Name}
+	var first *Processor
+	for _, each := range component {
+		processor := v.processors[each]
+		if processor.Kind != FlipFlop && processor != hub {
+			return Counter{}, fmt.Errorf("%s %s is in the counter", processor.Kind, each)
+		}
+		if processor.Kind == FlipFlop && slices.Contains(processor.Inputs, initName) {
+			if first != nil {
+				return Counter{}, fmt.Errorf("both %s and %s are bit 0", first.Name, each)
+			}
+			first = processor
+		}
+	}
+	for _, each := range hub.Inputs {
+		if !slices.Contains(component, each) {
+			return Counter{}, fmt.Errorf("%s feeds the hub from outside of the counter", each)
+		}
+	}
+	if first == nil {
+		return Counter{}, fmt.Errorf("broadcaster doesn't feed it")
+	}
+	outside := slices.DeleteFunc(slices.Clone(hub.Targets), func(target string) bool {
+		return slices.Contains(component, target)
+	})
+	if len(outside) != 1 {
+		return Counter{}, fmt.Errorf("hub should send out of the counter once, it sends to %v",
+			outside)
+	}
+
+	for current := first; current != nil; {
+		counter.Bits = append(counter.Bits, current.Name)
+		var next *Processor
+		for _, target := range current.Targets {
+			if target == hub.Name {
+				continue
+			}
+			processor := v.processors[target]
+			if processor == nil || processor.Kind != FlipFlop || next != nil {
+				return Counter{}, fmt.Errorf("%s should send to the hub and the next bit", current.Name)
+			}
+			next = processor
+		}
+		if next != nil && slices.Contains(counter.Bits, next.Name) {
+			return Counter{}, fmt.Errorf("bits loop at %s", next.Name)
+		}
+		current = next
+	}
+	if len(counter.Bits) != len(component)-1 {
+		return Counter{}, fmt.Errorf("the chain covers %d of %d bits",
+			len(counter.Bits),
+			len(component)-1)
+	}
+
+	for i, each := range counter.Bits {
+		feeds := slices.Contains(hub.Inputs, each)
+		reset := slices.Contains(hub.Targets, each)
+		switch {
+		case i == 0 && (!feeds || !reset):
+			return Counter{}, fmt.Errorf("bit 0, %s, should feed the hub and be reset by it", each)
+		case i == len(counter.Bits)-1 && !feeds:
+			return Counter{}, fmt.Errorf("the last bit, %s, should feed the hub", each)
+		case i > 0 && feeds == reset:
+			return Counter{}, fmt.Errorf("bit %d, %s, should either feed the hub or be reset", i, each)
+		}
+		if feeds {
+			counter.Period |= 1 << i
+			if v.processors[each].On != (hub.Memory[each] == High) {
+				return Counter{}, fmt.Errorf("the hub remembers %s wrong", each)
+			}
+		}
+		if v.processors[each].On {
+			counter.Value |= 1 << i
+		}
+	}
+	if counter.Value&counter.Period == counter.Period {
+		return Counter{}, fmt.Errorf("it's at %d and should have fired already", counter.Value)
+	}
+	counter.First = deriveFirstFiring(counter.Value, counter.Period)
+	// The hub's reset adds the bits that aren't in Period and one more, which carries over the
+	// last bit. Only the bits that were on and aren't in Period are left on.
+	left := (counter.Value + counter.First) &^ counter.Period
+	counter.Phase = (counter.First + counter.Period - left) % counter.Period
+	return counter, nil
+}
+
+// deriveFirstFiring derives the first press on which a counter that is at value now fires. It
+// fires at the smallest count above value that has all the bits of period on. The counter can't
+// wrap before that because the last bit is in period.
+func deriveFirstFiring(value, period int) int {
+	next := value + 1
+	if missing := period &^ next; missing != 0 {
+		// Set the highest missing bit, clear the bits below it and set the rest of period.
+		highest := bits.Len(uint(missing)) - 1
+		next = (next>>highest|1)<<highest | period
+	}
+	return next - value
+}
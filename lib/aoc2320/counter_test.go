@@ -0,0 +1,278 @@
+package aoc2320
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/gent"
+	"github.com/stretchr/testify/require"
+)
+
+// generateCounter generates the lines of a counter that fires every period presses. Its bits are
+// named <prefix>0, <prefix>1, ... and its hub <prefix>h.
+func generateCounter(prefix string, period int) []string {
+	hub := prefix + "h"
+	hubTargets := []string{prefix + "i"}
+	var lines []string
+	bitCount := bits.Len(uint(period))
+	for i := range bitCount {
+		var targets []string
+		if i < bitCount-1 {
+			targets = append(targets, fmt.Sprintf("%s%d", prefix, i+1))
+		}
+		if period&(1<<i) != 0 {
+			targets = append(targets, hub)
+		}
+		if i == 0 || period&(1<<i) == 0 {
+			hubTargets = append(hubTargets, fmt.Sprintf("%s%d", prefix, i))
+		}
+		lines = append(lines, fmt.Sprintf("%%%s%d -> %s", prefix, i, strings.Join(targets, ", ")))
+	}
+	return append(
+		lines,
+		fmt.Sprintf("&%s -> %s", hub, strings.Join(hubTargets, ", ")),
+		fmt.Sprintf("&%si -> fin", prefix))
+}
+
+func generateNetwork(periods ...int) []string {
+	var starts []string
+	var lines []string
+	for i, each := range periods {
+		prefix := string(rune('a' + i))
+		starts = append(starts, prefix+"0")
+		lines = append(lines, generateCounter(prefix, each)...)
+	}
+	return append(
+		lines,
+		"broadcaster -> "+strings.Join(starts, ", "),
+		"&fin -> rx")
+}
+
+// pressUntilLow presses the button until rx receives low.
+func pressUntilLow(squad *FiringSquad, limit int) int {
+	for i := 1; i <= limit; i++ {
+		if slices.Contains(squad.Press(), Signal{From: "fin", To: "rx", Pulse: Low}) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestAnalyzeActivation(t *testing.T) {
+	run := func(periods ...int) {
+		t.Run(fmt.Sprint(periods), func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			squad := NewFiringSquad(generateNetwork(periods...))
+
+			// EXERCISE
+			activation, err := squad.AnalyzeActivation("rx")
+
+			// VERIFY
+			req.NoError(err)
+			req.Equal("fin", activation.Final)
+			req.Equal(len(periods), len(activation.Counters))
+			for i, each := range activation.Counters {
+				req.Equal(periods[i], each.Period)
+				req.Equal(0, each.Phase)
+				req.Equal(periods[i], each.First)
+			}
+			req.Equal(activation.Presses, pressUntilLow(squad, activation.Presses))
+		})
+	}
+
+	run(11)
+	run(11, 13)
+	run(13, 29, 9)
+	run(6*1+1, 15, 21)
+	run(3923)
+}
+
+func TestAnalyzeActivationCounterBits(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	squad := NewFiringSquad(generateNetwork(11))
+
+	// EXERCISE
+	activation, err := squad.AnalyzeActivation("rx")
+
+	// VERIFY
+	req.NoError(err)
+	req.Equal(
+		[]Counter{{Hub: "ah", Bits: []string{"a0", "a1", "a2", "a3"}, Period: 11, First: 11}},
+		activation.Counters)
+}
+
+func TestAnalyzeActivationAfterPresses(t *testing.T) {
+	run := func(presses int, periods []int, expectedPhases []int) {
+		t.Run(fmt.Sprint(presses, periods), func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			squad := NewFiringSquad(generateNetwork(periods...))
+			for range presses {
+				squad.Press()
+			}
+
+			// EXERCISE
+			activation, err := squad.AnalyzeActivation("rx")
+
+			// VERIFY
+			req.NoError(err)
+			req.Equal(expectedPhases, gent.Map(activation.Counters, func(c Counter) int {
+				return c.Phase
+			}))
+			req.Equal(activation.Presses, pressUntilLow(squad, activation.Presses))
+		})
+	}
+
+	run(5, []int{11, 13}, []int{6, 8})
+	// Counter a has fired once and is at 3 again.
+	run(14, []int{11, 13, 9}, []int{8, 12, 4})
+	run(40, []int{13, 29, 9}, []int{12, 18, 5})
+}
+
+func TestAnalyzeActivationRestored(t *testing.T) {
+	run := func(name string, on []string, expected Counter, expectedPresses int) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			// Counter a counts to 13, 1101, and only bit 1 is reset by the hub.
+			squad := NewFiringSquad(generateNetwork(13, 11))
+			state := squad.Snapshot()
+			for _, each := range on {
+				state.FlipFlops[each] = true
+				if each != "a1" {
+					state.Conjunctions["ah"][each] = High
+				}
+			}
+			req.NoError(squad.Restore(state))
+
+			// EXERCISE
+			activation, err := squad.AnalyzeActivation("rx")
+
+			// VERIFY
+			req.NoError(err)
+			req.Equal(expected, activation.Counters[0])
+			req.Equal(expectedPresses, activation.Presses)
+			req.Equal(expectedPresses, pressUntilLow(squad, expectedPresses))
+		})
+	}
+
+	bits := []string{"a0", "a1", "a2", "a3"}
+	// At 6, 0110. The next count with 1101 on is 13, after which the counter is at 0.
+	run(
+		"reset to 0",
+		[]string{"a1", "a2"},
+		Counter{Hub: "ah", Bits: bits, Value: 6, Period: 13, Phase: 7, First: 7},
+		33)
+	// At 14, 1110. It fires at 15, 1111, and the reset leaves bit 1 on so it fires again after 11
+	// presses.
+	run(
+		"bit left on",
+		[]string{"a1", "a2", "a3"},
+		Counter{Hub: "ah", Bits: bits, Value: 14, Period: 13, Phase: 12, First: 1},
+		77)
+}
+
+func TestAnalyzeActivationFirstFirings(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	squad := NewFiringSquad(generateNetwork(13, 11))
+	state := squad.Snapshot()
+	// Both counters fire on the first press, a at 15 and b at 11. The congruences of the later
+	// firings only meet on press 12.
+	for _, each := range []string{"a1", "a2", "a3", "b1", "b3"} {
+		state.FlipFlops[each] = true
+	}
+	for _, each := range []string{"a2", "a3", "b1", "b3"} {
+		state.Conjunctions[string(each[0])+"h"][each] = High
+	}
+	req.NoError(squad.Restore(state))
+
+	// EXERCISE
+	activation, err := squad.AnalyzeActivation("rx")
+
+	// VERIFY
+	req.NoError(err)
+	req.Equal(1, activation.Presses)
+	req.Equal(1, pressUntilLow(squad, 1))
+}
+
+func TestAnalyzeActivationStateErrors(t *testing.T) {
+	run := func(name string, change func(state State), expected string) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			squad := NewFiringSquad(generateNetwork(13))
+			state := squad.Snapshot()
+			change(state)
+			require.NoError(t, squad.Restore(state))
+
+			// EXERCISE
+			_, err := squad.AnalyzeActivation("rx")
+
+			// VERIFY
+			require.EqualError(t, err, expected)
+		})
+	}
+
+	run(
+		"memory",
+		func(state State) { state.FlipFlops["a2"] = true },
+		"counter of ah: the hub remembers a2 wrong")
+	run(
+		"fired",
+		func(state State) {
+			for _, each := range []string{"a0", "a2", "a3"} {
+				state.FlipFlops[each] = true
+				state.Conjunctions["ah"][each] = High
+			}
+		},
+		"counter of ah: it's at 13 and should have fired already")
+}
+
+func TestAnalyzeActivationErrors(t *testing.T) {
+	run := func(name string, lines []string, expected string) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+
+			// EXERCISE
+			_, err := NewFiringSquad(lines).AnalyzeActivation("rx")
+
+			// VERIFY
+			require.EqualError(t, err, expected)
+		})
+	}
+
+	replace := func(lines []string, old string, replacements ...string) []string {
+		return append(slices.DeleteFunc(slices.Clone(lines), func(s string) bool {
+			return s == old
+		}), replacements...)
+	}
+
+	network := generateNetwork(11, 13)
+	run("no rx", []string{"broadcaster -> a", "%a -> b"}, "rx should have one caller, it has 0")
+	run(
+		"flip-flop caller",
+		replace(network, "&fin -> rx", "%fin -> rx"),
+		"fin, the caller of rx, isn't a conjunction")
+	run(
+		"two inverters",
+		replace(network, "&ai -> fin", "&ai -> aj", "&aj -> fin"),
+		"aj receives low when counter ah fires")
+	run(
+		"hub doesn't reset bit 0",
+		replace(network, "&ah -> ai, a0, a2", "&ah -> ai, a2"),
+		"counter of ah: a0 feeds the hub from outside of the counter")
+	run(
+		"bit feeds and is reset",
+		replace(network, "&ah -> ai, a0, a2", "&ah -> ai, a0, a1, a2"),
+		"counter of ah: bit 1, a1, should either feed the hub or be reset")
+	run(
+		"branching chain",
+		replace(network, "%a1 -> a2, ah", "%a1 -> a2, a3, ah"),
+		"counter of ah: a1 should send to the hub and the next bit")
+}
@@ -0,0 +1,92 @@
+package aoc2320
+
+import (
+	"fmt"
+	"maps"
+)
+
+// State is the state of every flip-flop and conjunction in the network. It can be serialised to
+// JSON.
+type State struct {
+	FlipFlops    map[string]bool             `json:"flipFlops"`
+	Conjunctions map[string]map[string]Pulse `json:"conjunctions"`
+}
+
+// Snapshot copies the state of the network.
+func (v *FiringSquad) Snapshot() State {
+	state := State{FlipFlops: map[string]bool{}, Conjunctions: map[string]map[string]Pulse{}}
+	for name, processor := range v.processors {
+		switch processor.Kind {
+		case FlipFlop:
+			state.FlipFlops[name] = processor.On
+		case Conjunction:
+			state.Conjunctions[name] = maps.Clone(processor.Memory)
+		}
+	}
+	return state
+}
+
+// Restore sets the state of the network. The state has to cover the network exactly. Nothing is
+// changed if it doesn't.
+func (v *FiringSquad) Restore(state State) error {
+	flipFlopCount, conjunctionCount := 0, 0
+	for name, processor := range v.processors {
+		switch processor.Kind {
+		case FlipFlop:
+			flipFlopCount++
+			if _, ok := state.FlipFlops[name]; !ok {
+				return fmt.Errorf("no state for flip-flop %s", name)
+			}
+		case Conjunction:
+			conjunctionCount++
+			memory, ok := state.Conjunctions[name]
+			if !ok {
+				return fmt.Errorf("no state for conjunction %s", name)
+			}
+			if len(memory) != len(processor.Memory) {
+				return fmt.Errorf(
+					"conjunction %s has %d inputs, state has %d",
+					name,
+					len(processor.Memory),
+					len(memory))
+			}
+			for input := range memory {
+				if _, ok := processor.Memory[input]; !ok {
+					return fmt.Errorf("%s isn't an input of conjunction %s", input, name)
+				}
+			}
+		}
+	}
+	if len(state.FlipFlops) != flipFlopCount || len(state.Conjunctions) != conjunctionCount {
+		return fmt.Errorf(
+			"state has %d flip-flops and %d conjunctions, network has %d and %d",
+			len(state.FlipFlops),
+			len(state.Conjunctions),
+			flipFlopCount,
+			conjunctionCount)
+	}
+
+	for name, on := range state.FlipFlops {
+		v.processors[name].On = on
+	}
+	for name, memory := range state.Conjunctions {
+		v.processors[name].Memory = maps.Clone(memory)
+	}
+	return nil
+}
+
+func (v Pulse) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+func (v *Pulse) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*v = Low
+	case "high":
+		*v = High
+	default:
+		return fmt.Errorf("not a pulse: %s", text)
+	}
+	return nil
+}
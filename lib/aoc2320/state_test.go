@@ -0,0 +1,146 @@
+package aoc2320
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/inr"
+	"github.com/denarced/gent"
+	"github.com/stretchr/testify/require"
+)
+
+func createSquad(filen string) *FiringSquad {
+	lines := gent.OrPanic2(inr.ReadPath(filepath.Join("testdata", filen)))("read test data")
+	return NewFiringSquad(lines)
+}
+
+func TestPress(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	squad := createSquad("in1.txt")
+
+	// EXERCISE
+	trace := squad.Press()
+
+	// VERIFY
+	req.Equal(
+		[]string{
+			"button -low-> broadcaster",
+			"broadcaster -low-> a",
+			"broadcaster -low-> b",
+			"broadcaster -low-> c",
+			"a -high-> b",
+			"b -high-> c",
+			"c -high-> inv",
+			"inv -low-> a",
+			"a -low-> b",
+			"b -low-> c",
+			"c -low-> inv",
+			"inv -high-> a",
+		},
+		gent.Map(trace, Signal.String))
+	req.Equal(
+		State{
+			FlipFlops:    map[string]bool{"a": false, "b": false, "c": false},
+			Conjunctions: map[string]map[string]Pulse{"inv": {"c": Low}},
+		},
+		squad.Snapshot())
+}
+
+func TestSnapshot(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	squad := createSquad("in2.txt")
+	squad.Press()
+	state := squad.Snapshot()
+	req.Equal(
+		State{
+			FlipFlops: map[string]bool{"a": true, "b": true},
+			Conjunctions: map[string]map[string]Pulse{
+				"inv": {"a": High},
+				"con": {"a": High, "b": High},
+			},
+		},
+		state)
+	expected := squad.Press()
+	squad.Press()
+
+	// EXERCISE
+	req.NoError(squad.Restore(state))
+
+	// VERIFY
+	req.Equal(expected, squad.Press())
+}
+
+func TestStateJSON(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	squad := createSquad("in2.txt")
+	squad.Press()
+	state := squad.Snapshot()
+
+	// EXERCISE
+	data, err := json.Marshal(state)
+	req.NoError(err)
+	var parsed State
+	req.NoError(json.Unmarshal(data, &parsed))
+
+	// VERIFY
+	req.Equal(
+		`{"flipFlops":{"a":true,"b":true},`+
+			`"conjunctions":{"con":{"a":"high","b":"high"},"inv":{"a":"high"}}}`,
+		string(data))
+	req.Equal(state, parsed)
+	req.EqualError(json.Unmarshal([]byte(`{"conjunctions":{"inv":{"a":"mid"}}}`), &parsed),
+		"not a pulse: mid")
+}
+
+func TestRestoreErrors(t *testing.T) {
+	run := func(name string, state State, expected string) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			squad := createSquad("in2.txt")
+			before := squad.Snapshot()
+
+			// EXERCISE
+			err := squad.Restore(state)
+
+			// VERIFY
+			req.EqualError(err, expected)
+			req.Equal(before, squad.Snapshot())
+		})
+	}
+
+	conjunctions := map[string]map[string]Pulse{"inv": {"a": High}, "con": {"a": Low, "b": Low}}
+	run(
+		"missing flip-flop",
+		State{FlipFlops: map[string]bool{"a": true}, Conjunctions: conjunctions},
+		"no state for flip-flop b")
+	run(
+		"extra flip-flop",
+		State{
+			FlipFlops:    map[string]bool{"a": true, "b": true, "x": true},
+			Conjunctions: conjunctions,
+		},
+		"state has 3 flip-flops and 2 conjunctions, network has 2 and 2")
+	run(
+		"unknown input",
+		State{
+			FlipFlops:    map[string]bool{"a": true, "b": true},
+			Conjunctions: map[string]map[string]Pulse{"inv": {"b": High}, "con": {"a": Low, "b": Low}},
+		},
+		"b isn't an input of conjunction inv")
+}
+
+func TestAll(t *testing.T) {
+	req := require.New(t)
+	squad := createSquad("in2.txt")
+	squad.Press()
+	req.True(squad.processors["a"].All(High))
+	req.False(squad.processors["a"].All(Low))
+	req.True(squad.processors["con"].All(High))
+	req.False(squad.processors[initName].All(Low))
+}
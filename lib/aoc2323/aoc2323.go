@@ -3,8 +3,11 @@ package aoc2323
 import (
 	"fmt"
 	"slices"
+	"strconv"
 
 	"github.com/denarced/advent-of-code/shared"
+	sharedgraph "github.com/denarced/advent-of-code/shared/graph"
+	"github.com/denarced/gent"
 )
 
 const (
@@ -215,7 +218,13 @@ func dive(
 }
 
 func FindLongestPathWithGraph(lines []string) int {
-	aGraph := parseGraph(lines)
+	maximum, _ := findLongestRoute(parseGraph(lines))
+	shared.Logger.Info("Max length derived.", "length", maximum)
+	return maximum
+}
+
+// findLongestRoute finds the length and the edges of the longest route from the start to the end.
+func findLongestRoute(aGraph graph) (int, []int) {
 	vToEdges := make([][]int, len(aGraph.vertices))
 	for i := range aGraph.vertices {
 		for j, anEdge := range aGraph.edges {
@@ -225,18 +234,62 @@ func FindLongestPathWithGraph(lines []string) int {
 		}
 	}
 	var maximum int
+	var route []int
 	done := func(edgePerm []int) {
 		var total int
 		for _, i := range edgePerm {
 			total += aGraph.edges[i].length
 		}
-		maximum = max(maximum, total)
+		if total > maximum {
+			maximum = total
+			route = slices.Clone(edgePerm)
+		}
 	}
 	used := make([]int, len(aGraph.vertices))
 	used[0] = 1
 	dive(aGraph, vToEdges, 0, used, nil, 1, done)
-	shared.Logger.Info("Max length derived.", "length", maximum)
-	return maximum
+	return maximum, route
+}
+
+// DrawJunctions creates a diagram of the junctions with edges weighted by their lengths. With
+// longest, the longest route is searched for, which is slow, and highlighted.
+func DrawJunctions(lines []string, longest bool) *sharedgraph.Diagram {
+	aGraph := parseGraph(lines)
+	shared.Logger.Info("Draw junctions.", "longest", longest)
+	toID := func(index int) string {
+		loc := aGraph.vertices[index].loc
+		return fmt.Sprintf("%d,%d", loc.X, loc.Y)
+	}
+	diagram := sharedgraph.NewDiagram(false)
+	for i := range aGraph.vertices {
+		kind := "junction"
+		switch i {
+		case 0:
+			kind = "start"
+		case 1:
+			kind = "end"
+		}
+		diagram.AddNode(toID(i), kind)
+	}
+	for _, each := range aGraph.edges {
+		diagram.AddEdge(toID(each.fromIndex), toID(each.toIndex), strconv.Itoa(each.length))
+	}
+	if !longest {
+		return diagram
+	}
+	_, route := findLongestRoute(aGraph)
+	shared.Logger.Info("Longest route found.", "edges", len(route))
+	ids := []string{toID(0)}
+	current := 0
+	for _, i := range route {
+		anEdge := aGraph.edges[i]
+		current = gent.Tri(anEdge.fromIndex == current, anEdge.toIndex, anEdge.fromIndex)
+		ids = append(ids, toID(current))
+	}
+	if err := diagram.HighlightPath(ids...); err != nil {
+		panic(fmt.Sprintf("Route isn't in the diagram: %s.", err))
+	}
+	return diagram
 }
 
 func parseGraph(lines []string) (result graph) {
@@ -1,6 +1,8 @@
 package aoc2323
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/denarced/advent-of-code/shared"
@@ -246,3 +248,33 @@ func TestExtractDirection(t *testing.T) {
 	req.Equal(shared.RealEast, startDir)
 	req.Equal(shared.RealSouth, endDir)
 }
+
+func TestDrawJunctions(t *testing.T) {
+	run := func(longest bool, expectedRouteCount int) {
+		t.Run(fmt.Sprint(longest), func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			lines := gent.OrPanic2(inr.ReadPath("testdata/in.txt"))("read test data")
+
+			// EXERCISE
+			dot := DrawJunctions(lines, longest).DOT()
+
+			// VERIFY
+			req.Contains(dot, `"19,3" -- "21,0" [label="5"`)
+			var edgeCount, routeCount int
+			for _, each := range strings.Split(dot, "\n") {
+				if strings.Contains(each, " -- ") {
+					edgeCount++
+					if strings.Contains(each, "color=red") {
+						routeCount++
+					}
+				}
+			}
+			req.Equal(12, edgeCount)
+			req.Equal(expectedRouteCount, routeCount)
+		})
+	}
+
+	run(false, 0)
+	run(true, 8)
+}
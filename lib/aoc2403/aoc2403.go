@@ -1,56 +1,206 @@
 package aoc2403
 
 import (
-	"regexp"
+	"errors"
+	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/denarced/advent-of-code/shared"
 )
 
+var errDisabled = errors.New("disabled by don't()")
+
+// State is what instructions operate on.
+type State struct {
+	Total    int
+	Disabled bool
+}
+
+// InstructionSpec defines an instruction: its name, the number of integer arguments, and what it
+// does. Execute rejects the instruction by returning an error.
+type InstructionSpec struct {
+	Name    string
+	Arity   int
+	Execute func(state *State, args []int) error
+}
+
 var (
-	mulPattern = regexp.MustCompile(`mul\(\d+,\d+\)|don't\(\)|do\(\)`)
+	mulSpec = InstructionSpec{
+		Name:  "mul",
+		Arity: 2,
+		Execute: func(state *State, args []int) error {
+			if state.Disabled {
+				return errDisabled
+			}
+			state.Total += args[0] * args[1]
+			return nil
+		},
+	}
+	doSpec = InstructionSpec{
+		Name: "do",
+		Execute: func(state *State, _ []int) error {
+			state.Disabled = false
+			return nil
+		},
+	}
+	dontSpec = InstructionSpec{
+		Name: "don't",
+		Execute: func(state *State, _ []int) error {
+			state.Disabled = true
+			return nil
+		},
+	}
 )
 
-func Multiply(text string, logic bool) int {
-	total := 0
-	skipping := false
-	for {
-		pair := mulPattern.FindStringIndex(text)
-		if pair == nil {
-			break
-		}
-		piece := text[pair[0]:pair[1]]
-		text = text[pair[1]:]
-
-		if strings.HasPrefix(piece, "don't") {
-			skipping = true
+// BasicInstructions only multiply.
+func BasicInstructions() []InstructionSpec {
+	return []InstructionSpec{mulSpec}
+}
+
+// ConditionalInstructions multiply unless disabled with don't() and enabled again with do().
+func ConditionalInstructions() []InstructionSpec {
+	return []InstructionSpec{mulSpec, doSpec, dontSpec}
+}
+
+// Token is a candidate instruction: a known instruction name in the text. Err tells why the
+// candidate isn't a valid instruction.
+type Token struct {
+	Offset int
+	// Text is what was read from the text, from the name to either the closing parenthesis or the
+	// character that broke the instruction.
+	Text string
+	Name string
+	Args []int
+	Err  error
+}
+
+// Tokenize finds the instructions of the instruction set in corrupted text. Each candidate is
+// returned, valid or not. When names overlap, e.g. "do" and "don't", the longest one is used.
+func Tokenize(text string, specs []InstructionSpec) []Token {
+	byLength := slices.Clone(specs)
+	slices.SortStableFunc(byLength, func(a, b InstructionSpec) int {
+		return len(b.Name) - len(a.Name)
+	})
+	var tokens []Token
+	for offset := 0; offset < len(text); {
+		spec, ok := findSpecAt(text, offset, byLength)
+		if !ok {
+			offset++
 			continue
 		}
-		if strings.HasPrefix(piece, "do") {
-			skipping = false
-			continue
+		token := readToken(text, offset, spec)
+		tokens = append(tokens, token)
+		if token.Err == nil {
+			offset += len(token.Text)
+		} else {
+			// Corruption can hide the start of the next instruction, e.g. "mul(mul(1,2)".
+			offset++
 		}
-		if logic && skipping {
-			continue
+	}
+	return tokens
+}
+
+func findSpecAt(text string, offset int, specs []InstructionSpec) (InstructionSpec, bool) {
+	for _, each := range specs {
+		if strings.HasPrefix(text[offset:], each.Name) {
+			return each, true
 		}
-		if strings.HasPrefix(piece, "mul") {
-			a, b := splitMul(piece)
-			total += a * b
+	}
+	return InstructionSpec{}, false
+}
+
+func readToken(text string, offset int, spec InstructionSpec) Token {
+	token := Token{Offset: offset, Name: spec.Name}
+	i := offset + len(spec.Name)
+	fail := func(err error) Token {
+		token.Text = text[offset:min(i+1, len(text))]
+		token.Args = nil
+		token.Err = err
+		return token
+	}
+	if i >= len(text) || text[i] != '(' {
+		return fail(fmt.Errorf("expected ( at %d", i))
+	}
+	i++
+	for len(token.Args) < spec.Arity {
+		if len(token.Args) > 0 {
+			if i >= len(text) || text[i] != ',' {
+				return fail(fmt.Errorf("expected , at %d", i))
+			}
+			i++
+		}
+		start := i
+		for i < len(text) && '0' <= text[i] && text[i] <= '9' {
+			i++
 		}
+		if start == i {
+			return fail(fmt.Errorf("expected digit at %d", i))
+		}
+		arg, err := strconv.Atoi(text[start:i])
+		if err != nil {
+			return fail(fmt.Errorf("invalid argument at %d: %w", start, err))
+		}
+		token.Args = append(token.Args, arg)
+	}
+	if i >= len(text) || text[i] != ')' {
+		return fail(fmt.Errorf("expected ) at %d", i))
 	}
-	return total
+	token.Text = text[offset : i+1]
+	return token
+}
+
+// Verdict is what happened to a candidate instruction. Err is nil for executed instructions.
+type Verdict struct {
+	Token Token
+	Err   error
 }
 
-//revive:disable-next-line:confusing-results
-func splitMul(s string) (int, int) {
-	separated := s[4 : len(s)-1]
-	broken := strings.Split(separated, ",")
-	return toInt(broken[0]), toInt(broken[1])
+// Report is the result of interpreting text: the final state and a verdict for every candidate.
+type Report struct {
+	State    State
+	Verdicts []Verdict
 }
 
-func toInt(s string) int {
-	i, err := strconv.Atoi(s)
-	if err != nil {
-		panic("Failed to convert to int: " + s)
+// Accepted returns the verdicts of the executed instructions.
+func (v Report) Accepted() []Verdict {
+	return slices.DeleteFunc(slices.Clone(v.Verdicts), func(each Verdict) bool {
+		return each.Err != nil
+	})
+}
+
+// Rejected returns the verdicts of the candidates that were either invalid or not executed.
+func (v Report) Rejected() []Verdict {
+	return slices.DeleteFunc(slices.Clone(v.Verdicts), func(each Verdict) bool {
+		return each.Err == nil
+	})
+}
+
+// Interpret executes the valid instructions of the text in order.
+func Interpret(text string, specs []InstructionSpec) Report {
+	shared.Logger.Info("Interpret.", "length", len(text), "instructions", len(specs))
+	byName := map[string]InstructionSpec{}
+	for _, each := range specs {
+		byName[each.Name] = each
+	}
+	var report Report
+	for _, each := range Tokenize(text, specs) {
+		err := each.Err
+		if err == nil {
+			err = byName[each.Name].Execute(&report.State, each.Args)
+		}
+		shared.Logger.Debug("Candidate.", "offset", each.Offset, "text", each.Text, "err", err)
+		report.Verdicts = append(report.Verdicts, Verdict{Token: each, Err: err})
+	}
+	shared.Logger.Info("Interpreted.", "total", report.State.Total, "candidates", len(report.Verdicts))
+	return report
+}
+
+func Multiply(text string, logic bool) int {
+	specs := BasicInstructions()
+	if logic {
+		specs = ConditionalInstructions()
 	}
-	return i
+	return Interpret(text, specs).State.Total
 }
@@ -1,6 +1,7 @@
 package aoc2403
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -25,3 +26,112 @@ func TestMultiply(t *testing.T) {
 	run("mul(2,3)do()mul(3,4)don't()mul(5,2)", true, 2*3+3*4)
 	run("don't()mulmulmul(2,3)mul(23,3)do()mul(3,4)", true, 3*4)
 }
+
+func TestTokenize(t *testing.T) {
+	run := func(name, text string, expected []Token) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+
+			// EXERCISE
+			actual := Tokenize(text, ConditionalInstructions())
+
+			// VERIFY
+			req.Equal(len(expected), len(actual), "%v", actual)
+			for i, each := range expected {
+				req.Equal(each.Offset, actual[i].Offset, "offset %d", i)
+				req.Equal(each.Text, actual[i].Text, "text %d", i)
+				req.Equal(each.Name, actual[i].Name, "name %d", i)
+				req.Equal(each.Args, actual[i].Args, "args %d", i)
+				if each.Err == nil {
+					req.NoError(actual[i].Err, "err %d", i)
+				} else {
+					req.EqualError(actual[i].Err, each.Err.Error(), "err %d", i)
+				}
+			}
+		})
+	}
+
+	run("empty", "", nil)
+	run("no candidates", "abc(1,2)", nil)
+	run("valid", "xmul(2,4)&", []Token{{Offset: 1, Text: "mul(2,4)", Name: "mul", Args: []int{2, 4}}})
+	run(
+		"longest name",
+		"don't()do()",
+		[]Token{
+			{Offset: 0, Text: "don't()", Name: "don't", Args: nil},
+			{Offset: 7, Text: "do()", Name: "do", Args: nil},
+		},
+	)
+	run(
+		"corrupted",
+		"mul(4*mul[3,7]mul(1,)mul ( 2 , 4 )",
+		[]Token{
+			{Offset: 0, Text: "mul(4*", Name: "mul", Err: errors.New("expected , at 5")},
+			{Offset: 6, Text: "mul[", Name: "mul", Err: errors.New("expected ( at 9")},
+			{Offset: 14, Text: "mul(1,)", Name: "mul", Err: errors.New("expected digit at 20")},
+			{Offset: 21, Text: "mul ", Name: "mul", Err: errors.New("expected ( at 24")},
+		},
+	)
+	run(
+		"hidden in corruption",
+		"mul(mul(1,2)",
+		[]Token{
+			{Offset: 0, Text: "mul(m", Name: "mul", Err: errors.New("expected digit at 4")},
+			{Offset: 4, Text: "mul(1,2)", Name: "mul", Args: []int{1, 2}},
+		},
+	)
+	run(
+		"unterminated",
+		"mul(1,2",
+		[]Token{{Offset: 0, Text: "mul(1,2", Name: "mul", Err: errors.New("expected ) at 7")}},
+	)
+}
+
+func TestInterpret(t *testing.T) {
+	req := require.New(t)
+	shared.InitTestLogging(t)
+	text := "xmul(2,4)&mul[3,7]!^don't()_mul(5,5)+mul(32,64](mul(11,8)undo()?mul(8,5))"
+
+	// EXERCISE
+	report := Interpret(text, ConditionalInstructions())
+
+	// VERIFY
+	req.Equal(48, report.State.Total)
+	req.False(report.State.Disabled)
+	req.Equal(
+		[]string{"mul(2,4)", "don't()", "do()", "mul(8,5)"},
+		toTexts(report.Accepted()))
+	req.Equal(
+		[]string{"mul[", "mul(5,5)", "mul(32,64]", "mul(11,8)"},
+		toTexts(report.Rejected()))
+	req.ErrorIs(report.Rejected()[1].Err, errDisabled)
+}
+
+func TestInterpretCustomInstruction(t *testing.T) {
+	req := require.New(t)
+	shared.InitTestLogging(t)
+	add := InstructionSpec{
+		Name:  "add",
+		Arity: 3,
+		Execute: func(state *State, args []int) error {
+			state.Total += args[0] + args[1] + args[2]
+			return nil
+		},
+	}
+
+	// EXERCISE
+	report := Interpret("add(1,2,3)mul(2,2)add(1,2)", []InstructionSpec{add, mulSpec})
+
+	// VERIFY
+	req.Equal(10, report.State.Total)
+	req.Equal([]string{"add(1,2)"}, toTexts(report.Rejected()))
+}
+
+func toTexts(verdicts []Verdict) []string {
+	texts := make([]string, 0, len(verdicts))
+	for _, each := range verdicts {
+		texts = append(texts, each.Token.Text)
+	}
+	return texts
+}
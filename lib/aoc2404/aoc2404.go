@@ -1,7 +1,14 @@
 package aoc2404
 
-import "github.com/denarced/advent-of-code/shared"
+import (
+	"strings"
 
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/gent"
+)
+
+// CountInTable counts the word in every direction it can be read in. Unlike with FindPattern, a
+// palindrome is counted in both of its directions and a one letter word in all eight.
 func CountInTable(table []string, word string) int {
 	if len(word) == 0 {
 		return 0
@@ -28,43 +35,27 @@ func countWordsAt(table []string, word string, row, col int) int {
 }
 
 func CountWordCrosses(table []string, word string) int {
-	locations := findWordLocations(table, word)
-	counts := map[shared.Loc]int{}
-	total := 0
-	for _, each := range locations {
-		if count, ok := counts[each]; ok {
-			if count > 1 {
-				panic("wtf")
-			}
-			counts[each]++
-			total++
-			shared.Logger.Info("Found word cross.", "location", each)
-		} else {
-			shared.Logger.Debug("Found half of word cross.", "location", each)
-			counts[each] = 1
-		}
-	}
-	return total
+	return len(FindPattern(table, crossPattern(word)))
 }
 
-func findWordLocations(table []string, word string) []shared.Loc {
+// crossPattern creates a pattern where the word crosses itself diagonally, e.g. "MAS" becomes
+// "M.S", ".A." and "M.S".
+func crossPattern(word string) Pattern {
 	if len(word)%2 != 1 {
 		panic("only works with odd length words: 3, 5, 7, ...")
 	}
-	var locations []shared.Loc
-	mid := len(word) / 2
-	for r := 0; r < len(table); r++ {
-		for c := 0; c < len(table[r]); c++ {
-			for _, each := range shared.RealMiddleDirections {
-				if readTableAt(table, r, c, len(word), each) == word {
-					x := r + each.X*mid
-					y := c + each.Y*mid
-					locations = append(locations, shared.Loc{X: x, Y: y})
-				}
-			}
-		}
+	rows := make([][]byte, len(word))
+	for i := range rows {
+		rows[i] = []byte(strings.Repeat(string(Wildcard), len(word)))
+	}
+	for i := range len(word) {
+		rows[i][i] = word[i]
+		rows[len(word)-1-i][i] = word[i]
+	}
+	return Pattern{
+		Rows:         gent.Map(rows, func(b []byte) string { return string(b) }),
+		Orientations: SquareOrientations,
 	}
-	return locations
 }
 
 func readTableAt(table []string, row, col, count int, dir shared.Direction) string {
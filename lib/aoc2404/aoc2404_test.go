@@ -19,6 +19,8 @@ func TestCountInTable(t *testing.T) {
 
 	run("empty", []string{}, "JOB", 0)
 	run("one horizontal", []string{"..XMAS.."}, "XMAS", 1)
+	run("palindrome", []string{"..ABA.."}, "ABA", 2)
+	run("one letter", []string{"X"}, "X", 8)
 	run(
 		"happy path",
 		[]string{
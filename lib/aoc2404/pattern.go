@@ -0,0 +1,193 @@
+package aoc2404
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/gent"
+)
+
+// Wildcard matches any character in a pattern.
+const Wildcard = '.'
+
+// Orientation places a pattern on a table: each pattern row is read along Along and the next row
+// starts one step across from the previous one. Like in readTableAt, X is the row and Y the column.
+type Orientation struct {
+	Along  shared.Direction
+	Across shared.Direction
+}
+
+func (v Orientation) String() string {
+	return fmt.Sprintf("along %d,%d across %d,%d", v.Along.X, v.Along.Y, v.Across.X, v.Across.Y)
+}
+
+var (
+	// Identity reads the pattern as it's written.
+	Identity = Orientation{Along: shared.Direction{X: 0, Y: 1}, Across: shared.Direction{X: 1, Y: 0}}
+	// SquareOrientations are the rotations and reflections of a pattern.
+	SquareOrientations = deriveOrientations(shared.RealPrimaryDirections)
+	// AllOrientations also include rotations by 45 degrees where the rows run diagonally.
+	AllOrientations = append(
+		deriveOrientations(shared.RealMiddleDirections),
+		SquareOrientations...)
+)
+
+func deriveOrientations(alongs []shared.Direction) []Orientation {
+	var orientations []Orientation
+	for _, each := range alongs {
+		orientations = append(
+			orientations,
+			Orientation{Along: each, Across: shared.Direction{X: each.Y, Y: -each.X}},
+			Orientation{Along: each, Across: shared.Direction{X: -each.Y, Y: each.X}})
+	}
+	return orientations
+}
+
+var orientationSets = map[string][]Orientation{
+	"none":   {Identity},
+	"square": SquareOrientations,
+	"all":    AllOrientations,
+}
+
+// Pattern is a small grid of characters searched for in a table.
+type Pattern struct {
+	Rows         []string
+	Orientations []Orientation
+}
+
+// OptionPrefix starts the option lines of a pattern. A row that starts with it is written with
+// the prefix doubled, e.g. "@@A" for the row "@A".
+const OptionPrefix = "@"
+
+// ParsePattern parses pattern lines. Option lines, e.g. "@orientations: all", come before the
+// rows. Orientations are "none", "square" (default) or "all".
+func ParsePattern(lines []string) (Pattern, error) {
+	pattern := Pattern{Orientations: SquareOrientations}
+	for _, each := range lines {
+		if strings.HasPrefix(each, OptionPrefix+OptionPrefix) {
+			pattern.Rows = append(pattern.Rows, strings.TrimPrefix(each, OptionPrefix))
+			continue
+		}
+		if !strings.HasPrefix(each, OptionPrefix) {
+			pattern.Rows = append(pattern.Rows, each)
+			continue
+		}
+		if len(pattern.Rows) > 0 {
+			return Pattern{}, fmt.Errorf("option after the rows: %s", each)
+		}
+		key, value, found := strings.Cut(strings.TrimPrefix(each, OptionPrefix), ":")
+		if !found {
+			return Pattern{}, fmt.Errorf("invalid pattern option: %s", each)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if key != "orientations" {
+			return Pattern{}, fmt.Errorf("unknown pattern option: %s", key)
+		}
+		orientations, ok := orientationSets[value]
+		if !ok {
+			return Pattern{}, fmt.Errorf("unknown orientations: %s", value)
+		}
+		pattern.Orientations = orientations
+	}
+	if len(pattern.Rows) == 0 {
+		return Pattern{}, fmt.Errorf("pattern has no rows")
+	}
+	return pattern, nil
+}
+
+// ReadPattern reads a pattern file. See ParsePattern for the format.
+func ReadPattern(filep string) (Pattern, error) {
+	lines, err := shared.ReadLinesFromFile(filep)
+	if err != nil {
+		return Pattern{}, err
+	}
+	pattern, err := ParsePattern(lines)
+	if err != nil {
+		return Pattern{}, fmt.Errorf("invalid pattern in %s: %w", filep, err)
+	}
+	return pattern, nil
+}
+
+// Match is where a pattern was found. Origin is where the first character of the first pattern row
+// is, X being the row and Y the column.
+type Match struct {
+	Origin      shared.Loc
+	Orientation Orientation
+}
+
+// FindPattern finds the pattern in the table. A match is a set of table cells that the pattern's
+// characters cover. When a pattern is symmetric, several orientations cover the same cells and only
+// the first of them is included: e.g. the X of "M.S", ".A." and "M.S" is found once, not twice, and
+// a palindrome such as "ABA" once, not in both directions.
+func FindPattern(table []string, pattern Pattern) []Match {
+	shared.Logger.Info(
+		"Find pattern.",
+		"rows",
+		pattern.Rows,
+		"orientations",
+		len(pattern.Orientations))
+	var matches []Match
+	seen := gent.NewSet[string]()
+	for r := 0; r < len(table); r++ {
+		for c := 0; c < len(table[r]); c++ {
+			for _, each := range pattern.Orientations {
+				if !matchesAt(table, pattern, r, c, each) {
+					continue
+				}
+				if !seen.Add(deriveMatchKey(pattern, r, c, each)) {
+					continue
+				}
+				matches = append(matches, Match{Origin: shared.Loc{X: r, Y: c}, Orientation: each})
+			}
+		}
+	}
+	shared.Logger.Info("Pattern found.", "count", len(matches))
+	return matches
+}
+
+func matchesAt(table []string, pattern Pattern, row, col int, orientation Orientation) bool {
+	for i, each := range pattern.Rows {
+		read := readTableAt(
+			table,
+			row+i*orientation.Across.X,
+			col+i*orientation.Across.Y,
+			len(each),
+			orientation.Along)
+		if !matchesRow(read, each) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesRow(read, row string) bool {
+	if len(read) != len(row) {
+		return false
+	}
+	for i := range len(row) {
+		if row[i] != Wildcard && row[i] != read[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// deriveMatchKey identifies the table cells that the pattern's non-wildcard characters cover.
+func deriveMatchKey(pattern Pattern, row, col int, orientation Orientation) string {
+	var cells []string
+	for i, each := range pattern.Rows {
+		for j := range len(each) {
+			if each[j] == Wildcard {
+				continue
+			}
+			r := row + i*orientation.Across.X + j*orientation.Along.X
+			c := col + i*orientation.Across.Y + j*orientation.Along.Y
+			cells = append(cells, fmt.Sprintf("%d,%d=%c", r, c, each[j]))
+		}
+	}
+	sort.Strings(cells)
+	return strings.Join(cells, ";")
+}
@@ -0,0 +1,239 @@
+package aoc2404
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"path/filepath"
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindPattern(t *testing.T) {
+	run := func(name string, table []string, pattern Pattern, expected []Match) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+
+			// EXERCISE
+			actual := FindPattern(table, pattern)
+
+			// VERIFY
+			req.Equal(expected, actual)
+		})
+	}
+
+	table := []string{
+		"ABC",
+		"DEF",
+		"GHI",
+	}
+	run("empty", []string{}, Pattern{Rows: []string{"A"}, Orientations: AllOrientations}, nil)
+	run(
+		"identity",
+		table,
+		Pattern{Rows: []string{"EF", "H."}, Orientations: []Orientation{Identity}},
+		[]Match{{Origin: shared.Loc{X: 1, Y: 1}, Orientation: Identity}},
+	)
+	run(
+		"rotated",
+		table,
+		Pattern{Rows: []string{"CF", "B."}, Orientations: SquareOrientations},
+		[]Match{{
+			Origin: shared.Loc{X: 0, Y: 2},
+			Orientation: Orientation{
+				Along:  shared.Direction{X: 1, Y: 0},
+				Across: shared.Direction{X: 0, Y: -1},
+			},
+		}},
+	)
+	run(
+		"reflected",
+		table,
+		Pattern{Rows: []string{"CB", "F."}, Orientations: SquareOrientations},
+		[]Match{{
+			Origin: shared.Loc{X: 0, Y: 2},
+			Orientation: Orientation{
+				Along:  shared.Direction{X: 0, Y: -1},
+				Across: shared.Direction{X: 1, Y: 0},
+			},
+		}},
+	)
+	run(
+		"not rotated",
+		table,
+		Pattern{Rows: []string{"CF", "B."}, Orientations: []Orientation{Identity}},
+		nil,
+	)
+	run(
+		"diagonal",
+		table,
+		Pattern{Rows: []string{"AEI"}, Orientations: AllOrientations},
+		[]Match{{
+			Origin: shared.Loc{X: 0, Y: 0},
+			Orientation: Orientation{
+				Along:  shared.Direction{X: 1, Y: 1},
+				Across: shared.Direction{X: 1, Y: -1},
+			},
+		}},
+	)
+	run(
+		"symmetric pattern matched once",
+		[]string{"ABA"},
+		Pattern{Rows: []string{"ABA"}, Orientations: SquareOrientations},
+		[]Match{{
+			Origin: shared.Loc{X: 0, Y: 0},
+			Orientation: Orientation{
+				Along:  shared.Direction{X: 0, Y: 1},
+				Across: shared.Direction{X: 1, Y: 0},
+			},
+		}},
+	)
+}
+
+func TestReadPattern(t *testing.T) {
+	table := []string{
+		"MMMSXXMASM",
+		"MSAMXMSMSA",
+		"AMXSXMAAMM",
+		"MSAMASMSMX",
+		"XMASAMXAMM",
+		"XXAMMXXAMA",
+		"SMSMSASXSS",
+		"SAXAMASAAA",
+		"MAMMMXMMMM",
+		"MXMXAXMASX",
+	}
+	run := func(filen string, expected int) {
+		t.Run(filen, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+
+			// EXERCISE
+			pattern, err := ReadPattern(filepath.Join("patterns", filen))
+
+			// VERIFY
+			req.NoError(err)
+			req.Len(FindPattern(table, pattern), expected)
+		})
+	}
+
+	run("xmas.txt", 18)
+	run("x-mas.txt", 9)
+}
+
+func TestParsePattern(t *testing.T) {
+	run := func(name string, lines []string, expected Pattern, expectedErr string) {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+
+			// EXERCISE
+			actual, err := ParsePattern(lines)
+
+			// VERIFY
+			if expectedErr != "" {
+				req.EqualError(err, expectedErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(expected, actual)
+		})
+	}
+
+	run("default", []string{"AB"}, Pattern{Rows: []string{"AB"}, Orientations: SquareOrientations}, "")
+	run(
+		"option",
+		[]string{"@orientations: none", "A.", "BC"},
+		Pattern{Rows: []string{"A.", "BC"}, Orientations: []Orientation{Identity}},
+		"",
+	)
+	run(
+		"colon in row",
+		[]string{"A:", ":B"},
+		Pattern{Rows: []string{"A:", ":B"}, Orientations: SquareOrientations},
+		"",
+	)
+	run(
+		"escaped prefix",
+		[]string{"@orientations: all", "@@A", "B@"},
+		Pattern{Rows: []string{"@A", "B@"}, Orientations: AllOrientations},
+		"",
+	)
+	run("no rows", []string{"@orientations: all"}, Pattern{}, "pattern has no rows")
+	run("unknown option", []string{"@size: 2", "AB"}, Pattern{}, "unknown pattern option: size")
+	run("invalid option", []string{"@all", "AB"}, Pattern{}, "invalid pattern option: @all")
+	run(
+		"option after rows",
+		[]string{"AB", "@orientations: all"},
+		Pattern{},
+		"option after the rows: @orientations: all",
+	)
+	run(
+		"unknown orientations",
+		[]string{"@orientations: some", "AB"},
+		Pattern{},
+		"unknown orientations: some",
+	)
+}
+
+// countCrossCentres counts the crosses the way CountWordCrosses did before patterns: the centres
+// that the word crosses diagonally twice.
+func countCrossCentres(table []string, word string) int {
+	counts := map[shared.Loc]int{}
+	mid := len(word) / 2
+	for r := range table {
+		for c := range len(table[r]) {
+			for _, each := range shared.RealMiddleDirections {
+				if readTableAt(table, r, c, len(word), each) == word {
+					counts[shared.Loc{X: r + each.X*mid, Y: c + each.Y*mid}]++
+				}
+			}
+		}
+	}
+	total := 0
+	for _, each := range counts {
+		if each == 2 {
+			total++
+		}
+	}
+	return total
+}
+
+func TestFindPatternDeduplication(t *testing.T) {
+	run := func(seed uint64) {
+		t.Run(fmt.Sprint(seed), func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			rng := rand.New(rand.NewPCG(seed, seed))
+			table := make([]string, 30)
+			for i := range table {
+				row := make([]byte, 30)
+				for j := range row {
+					row[j] = "XMAS"[rng.IntN(4)]
+				}
+				table[i] = string(row)
+			}
+
+			find := func(word string) int {
+				return len(FindPattern(
+					table,
+					Pattern{Rows: []string{word}, Orientations: AllOrientations}))
+			}
+
+			// EXERCISE & VERIFY
+			// Only symmetric orientations are dropped so words that aren't palindromes are found
+			// once per direction.
+			req.Equal(CountInTable(table, "XMAS"), find("XMAS"))
+			// The X is symmetric: two orientations cover each of them.
+			req.Equal(countCrossCentres(table, "MAS"), CountWordCrosses(table, "MAS"))
+			// A palindrome is found once instead of in both directions.
+			req.Equal(CountInTable(table, "SAS")/2, find("SAS"))
+			req.Equal(CountInTable(table, "S")/8, find("S"))
+		})
+	}
+
+	for seed := range uint64(5) {
+		run(seed)
+	}
+}
@@ -1,6 +1,8 @@
 package aoc2405
 
 import (
+	"fmt"
+	"maps"
 	"slices"
 	"strconv"
 	"strings"
@@ -9,15 +11,15 @@ import (
 	"github.com/denarced/gent"
 )
 
-func SumCorrectMiddlePageNumbers(lines []string) int {
+func SumCorrectMiddlePageNumbers(lines []string) (int, error) {
 	return sumMiddlePageNumbers(lines, true)
 }
 
-func SumIncorrectMiddlePageNumbers(lines []string) int {
+func SumIncorrectMiddlePageNumbers(lines []string) (int, error) {
 	return sumMiddlePageNumbers(lines, false)
 }
 
-func sumMiddlePageNumbers(lines []string, correct bool) int {
+func sumMiddlePageNumbers(lines []string, correct bool) (int, error) {
 	rules, pages := toRulesAndPages(lines)
 	shared.Logger.Info(
 		"Sum middle page numbers.",
@@ -29,25 +31,21 @@ func sumMiddlePageNumbers(lines []string, correct bool) int {
 		correct,
 	)
 	sum := 0
-	filtered := gent.Filter(
-		pages,
-		func(s []int) bool {
-			return isSortedAccordingToRules(rules, s) == correct
-		})
-	shared.Logger.Info(
-		"Page lists filtered.",
-		"page list count",
-		len(filtered))
-	for _, each := range filtered {
-		if !correct {
-			each = sortWithRules(rules, each)
+	for _, each := range pages {
+		ordered, err := orderPages(rules, each)
+		if err != nil {
+			shared.Logger.Error("Failed to order pages.", "pages", each, "err", err)
+			return 0, err
+		}
+		if slices.Equal(each, ordered) != correct {
+			continue
 		}
-		middle := each[len(each)/2]
-		shared.Logger.Debug("Add middle page to the sum.", "pages", each, "middle", middle)
+		middle := ordered[len(ordered)/2]
+		shared.Logger.Debug("Add middle page to the sum.", "pages", ordered, "middle", middle)
 		sum += middle
 	}
 	shared.Logger.Info("Middle page numbers summed.", "sum", sum)
-	return sum
+	return sum, nil
 }
 
 func toRulesAndPages(lines []string) ([][]int, [][]int) {
@@ -82,34 +80,97 @@ func toRulesAndPages(lines []string) ([][]int, [][]int) {
 	return filterAndSplit("|"), filterAndSplit(",")
 }
 
-func isSortedAccordingToRules(rules [][]int, pages []int) bool {
-	sorted := sortWithRules(rules, pages)
-	return slices.Equal(pages, sorted)
-}
+// orderPages orders the pages with Kahn's algorithm over the rules that concern them. The rules
+// must order the pages completely: it's an error if they contain a cycle or if some pages could
+// be in either order.
+func orderPages(rules [][]int, pages []int) ([]int, error) {
+	inDegrees := make(map[int]int, len(pages))
+	for _, each := range pages {
+		if _, ok := inDegrees[each]; ok {
+			return nil, fmt.Errorf("page %d appears twice in %v", each, pages)
+		}
+		inDegrees[each] = 0
+	}
+	afters := map[int][]int{}
+	for _, each := range rules {
+		_, firstOk := inDegrees[each[0]]
+		_, secondOk := inDegrees[each[1]]
+		if firstOk && secondOk {
+			afters[each[0]] = append(afters[each[0]], each[1])
+			inDegrees[each[1]]++
+		}
+	}
 
-func sortWithRules(rules [][]int, pages []int) []int {
-	dup := append([]int{}, pages...)
-	slices.SortStableFunc(
-		dup,
-		func(a, b int) int {
-			rule := findRelevantRule(rules, a, b)
-			if rule == nil {
-				return 0
-			}
-			if rule[0] == a {
-				return -1
+	var ready []int
+	for _, each := range pages {
+		if inDegrees[each] == 0 {
+			ready = append(ready, each)
+		}
+	}
+	ordered := make([]int, 0, len(pages))
+	for len(ready) > 0 {
+		if len(ready) > 1 {
+			return nil, fmt.Errorf(
+				"ambiguous order for %v: no rule orders pages %v after %v",
+				pages,
+				ready,
+				ordered,
+			)
+		}
+		current := ready[0]
+		ready = nil
+		ordered = append(ordered, current)
+		for _, each := range afters[current] {
+			inDegrees[each]--
+			if inDegrees[each] == 0 {
+				ready = append(ready, each)
 			}
-			return 1
-		})
-	return dup
+		}
+	}
+	if len(ordered) < len(pages) {
+		return nil, fmt.Errorf(
+			"rule cycle for %v: %s",
+			pages,
+			strings.Join(findRuleCycle(afters, inDegrees), " -> "),
+		)
+	}
+	return ordered, nil
 }
 
-func findRelevantRule(rules [][]int, a, b int) []int {
-	var relevant []int
-	for _, each := range rules {
-		if a == each[0] && b == each[1] || a == each[1] && b == each[0] {
-			return each
+// findRuleCycle finds a cycle among the pages that Kahn's algorithm couldn't order. Each of them
+// still has a rule from another such page so walking the rules backwards must loop. The cycle is
+// returned as rules, e.g. "47|53". The pages are walked in order, and from the lowest one, so that
+// the same cycle is found every time when there are several.
+func findRuleCycle(afters map[int][]int, inDegrees map[int]int) []string {
+	befores := map[int]int{}
+	for _, before := range slices.Sorted(maps.Keys(afters)) {
+		if inDegrees[before] == 0 {
+			continue
+		}
+		for _, after := range afters[before] {
+			if _, ok := befores[after]; !ok && inDegrees[after] > 0 {
+				befores[after] = before
+			}
+		}
+	}
+	current := slices.Min(slices.Collect(maps.Keys(befores)))
+	steps := map[int]int{}
+	var walk []int
+	for {
+		if at, ok := steps[current]; ok {
+			cycle := walk[at:]
+			slices.Reverse(cycle)
+			// Start from the lowest page to make the result stable.
+			first := slices.Index(cycle, slices.Min(cycle))
+			cycle = slices.Concat(cycle[first:], cycle[:first])
+			chain := make([]string, len(cycle))
+			for i, each := range cycle {
+				chain[i] = fmt.Sprintf("%d|%d", each, cycle[(i+1)%len(cycle)])
+			}
+			return chain
 		}
+		steps[current] = len(walk)
+		walk = append(walk, current)
+		current = befores[current]
 	}
-	return relevant
 }
@@ -10,7 +10,9 @@ import (
 func TestSumCorrectMiddlePageNumbers(t *testing.T) {
 	shared.InitTestLogging(t)
 	// 143 is from the problem description.
-	require.Equal(t, 143, SumCorrectMiddlePageNumbers(advent05Lines()))
+	sum, err := SumCorrectMiddlePageNumbers(advent05Lines())
+	require.NoError(t, err)
+	require.Equal(t, 143, sum)
 }
 
 func advent05Lines() []string {
@@ -49,5 +51,93 @@ func advent05Lines() []string {
 func TestSumIncorrectMiddlePageNumbers(t *testing.T) {
 	shared.InitTestLogging(t)
 	// 123 is from the problem description.
-	require.Equal(t, 123, SumIncorrectMiddlePageNumbers(advent05Lines()))
+	sum, err := SumIncorrectMiddlePageNumbers(advent05Lines())
+	require.NoError(t, err)
+	require.Equal(t, 123, sum)
+}
+
+func TestSumMiddlePageNumbersWithInvalidRules(t *testing.T) {
+	shared.InitTestLogging(t)
+	_, err := SumCorrectMiddlePageNumbers(append(advent05Lines(), "13|75"))
+	require.ErrorContains(t, err, "rule cycle for [75 29 13]: 13|75 -> 75|29 -> 29|13")
+	_, err = SumIncorrectMiddlePageNumbers(append(advent05Lines(), "1,2"))
+	require.ErrorContains(t, err, "ambiguous order for [1 2]")
+}
+
+func TestOrderPages(t *testing.T) {
+	run := func(name string, rules [][]int, pages, expected []int, expectedErr string) {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+
+			// EXERCISE
+			actual, err := orderPages(rules, pages)
+
+			// VERIFY
+			if expectedErr != "" {
+				req.EqualError(err, expectedErr)
+				req.Nil(actual)
+				return
+			}
+			req.NoError(err)
+			req.Equal(expected, actual)
+		})
+	}
+
+	run("empty", nil, []int{}, []int{}, "")
+	run("single", nil, []int{1}, []int{1}, "")
+	run("ordered", [][]int{{1, 2}}, []int{1, 2}, []int{1, 2}, "")
+	run("unordered", [][]int{{1, 2}}, []int{2, 1}, []int{1, 2}, "")
+	run("irrelevant rules", [][]int{{3, 1}, {1, 2}, {4, 5}}, []int{2, 1}, []int{1, 2}, "")
+	run("chain", [][]int{{3, 1}, {2, 3}}, []int{1, 2, 3}, []int{2, 3, 1}, "")
+	run(
+		"ambiguous",
+		[][]int{{1, 2}, {1, 3}},
+		[]int{3, 2, 1},
+		nil,
+		"ambiguous order for [3 2 1]: no rule orders pages [2 3] after [1]",
+	)
+	run(
+		"ambiguous start",
+		nil,
+		[]int{2, 1},
+		nil,
+		"ambiguous order for [2 1]: no rule orders pages [2 1] after []",
+	)
+	run(
+		"cycle",
+		[][]int{{5, 1}, {1, 2}, {2, 3}, {3, 1}},
+		[]int{3, 2, 1, 5},
+		nil,
+		"rule cycle for [3 2 1 5]: 1|2 -> 2|3 -> 3|1",
+	)
+	run("two-cycle", [][]int{{1, 2}, {2, 1}}, []int{1, 2}, nil, "rule cycle for [1 2]: 1|2 -> 2|1")
+	run("duplicate", nil, []int{1, 1}, nil, "page 1 appears twice in [1 1]")
+}
+
+func TestOrderPagesWithSeveralCycles(t *testing.T) {
+	run := func(name string, rules [][]int, pages []int, expectedErr string) {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+
+			// Map iteration order varies from one call to the next.
+			for range 50 {
+				// EXERCISE
+				_, err := orderPages(rules, pages)
+
+				// VERIFY
+				req.EqualError(err, expectedErr)
+			}
+		})
+	}
+
+	run(
+		"separate",
+		[][]int{{3, 4}, {4, 3}, {1, 2}, {2, 1}},
+		[]int{4, 3, 2, 1},
+		"rule cycle for [4 3 2 1]: 1|2 -> 2|1")
+	run(
+		"shared page",
+		[][]int{{2, 3}, {3, 1}, {1, 2}, {2, 1}, {5, 3}, {3, 5}},
+		[]int{5, 3, 2, 1},
+		"rule cycle for [5 3 2 1]: 1|2 -> 2|1")
 }
@@ -6,9 +6,14 @@ import (
 	"sync"
 
 	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/render"
 	"github.com/denarced/gent"
 )
 
+// FrameCb receives the board after each step of the guard's patrol when set. Visited locations are
+// marked with "X" and the guard with its direction.
+var FrameCb func(brd *shared.Board)
+
 type vector struct {
 	loc shared.Loc
 	dir shared.Direction
@@ -122,11 +127,15 @@ func (v *fatBoard) print() string {
 			locToDirs[l] = []shared.Direction{v.dir}
 		}
 	})
+	route := render.Layer{}
 	for loc, dirs := range locToDirs {
-		v.nestedBrd.Set(loc, deriveDirCharacter(dirs))
+		route[loc] = render.Cell{Char: deriveDirCharacter(dirs), Color: render.Yellow}
 	}
-	v.nestedBrd.Set(v.curr.loc, '*')
-	return strings.Join(v.nestedBrd.GetLines(), "\n") + "\n"
+	scene := render.NewScene(
+		v.nestedBrd,
+		route,
+		render.Mark([]shared.Loc{v.curr.loc}, '*', render.Red))
+	return strings.Join(render.Lines(scene), "\n") + "\n"
 }
 
 func CountDistinctPositions(lines []string) int {
@@ -136,6 +145,11 @@ func CountDistinctPositions(lines []string) int {
 	shared.Logger.Info("Count distinct positions.", "line count", len(lines))
 	brd := shared.NewBoard(lines)
 	fatBrd := newFatBoard(vector{loc: brd.FindOrDie('^'), dir: shared.RealNorth}, brd)
+	var frame *shared.Board
+	if FrameCb != nil {
+		frame = brd.Copy()
+		FrameCb(frame)
+	}
 	counter := 7_000
 	for {
 		next := fatBrd.deriveNextLocation()
@@ -144,6 +158,7 @@ func CountDistinctPositions(lines []string) int {
 		}
 		if fatBrd.isBlock(next) {
 			fatBrd.turn()
+			recordGuard(frame, fatBrd.curr, fatBrd.curr.loc)
 			continue
 		}
 		shared.Logger.Debug("Step.", "previous", fatBrd.curr, "next", next)
@@ -152,7 +167,9 @@ func CountDistinctPositions(lines []string) int {
 			"step",
 			fmt.Sprintf("%s -> %s", fatBrd.curr.loc.ToString(), next.ToString()),
 		)
+		previous := fatBrd.curr.loc
 		fatBrd.move(next)
+		recordGuard(frame, fatBrd.curr, previous)
 		counter--
 		if counter < 0 {
 			panic("This loop is clearly eternal.")
@@ -161,6 +178,20 @@ func CountDistinctPositions(lines []string) int {
 	return fatBrd.deriveVisitedCount()
 }
 
+func recordGuard(frame *shared.Board, guard vector, previous shared.Loc) {
+	if frame == nil {
+		return
+	}
+	frame.Set(previous, 'X')
+	frame.Set(guard.loc, map[shared.Direction]rune{
+		shared.RealNorth: '^',
+		shared.RealEast:  '>',
+		shared.RealSouth: 'v',
+		shared.RealWest:  '<',
+	}[guard.dir])
+	FrameCb(frame)
+}
+
 func CountBlocksForIndefiniteLoops(lines []string) *gent.Set[shared.Loc] {
 	if len(lines) == 0 {
 		return gent.NewSet[shared.Loc]()
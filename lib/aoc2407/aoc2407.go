@@ -2,6 +2,8 @@ package aoc2407
 
 import (
 	"fmt"
+	"math"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -9,21 +11,292 @@ import (
 	"github.com/denarced/gent"
 )
 
-func DeriveCalibrationSum(lines []string, withConcat bool) int {
+// Operator is an operator in a calibration equation. Equations are evaluated left to right.
+type Operator struct {
+	Symbol string
+	// Apply applies the operator. It's not ok if the result isn't defined, e.g. when division
+	// isn't exact.
+	Apply func(left, right int) (int, bool)
+	// Undo derives the left operands for which Apply(left, right) is result. It's not ok when
+	// there are too many of them to list, e.g. any left multiplied by zero is zero. Then the left
+	// side is evaluated forward instead.
+	Undo func(result, right int) (lefts []int, ok bool)
+}
+
+var registry = map[string]Operator{}
+
+// Register registers an operator so that LookupOperators can find it by its symbol.
+func Register(op Operator) {
+	if _, exists := registry[op.Symbol]; exists {
+		panic(fmt.Sprintf("Operator already registered: %s.", op.Symbol))
+	}
+	registry[op.Symbol] = op
+}
+
+// LookupOperators looks up registered operators by their symbols.
+func LookupOperators(symbols ...string) ([]Operator, error) {
+	operators := make([]Operator, 0, len(symbols))
+	for _, each := range symbols {
+		op, ok := registry[each]
+		if !ok {
+			return nil, fmt.Errorf("unknown operator: %s", each)
+		}
+		operators = append(operators, op)
+	}
+	return operators, nil
+}
+
+func init() {
+	Register(Operator{
+		Symbol: "+",
+		Apply:  func(left, right int) (int, bool) { return left + right, true },
+		Undo:   func(result, right int) ([]int, bool) { return []int{result - right}, true },
+	})
+	Register(Operator{
+		Symbol: "-",
+		Apply:  func(left, right int) (int, bool) { return left - right, true },
+		Undo:   func(result, right int) ([]int, bool) { return []int{result + right}, true },
+	})
+	Register(Operator{
+		Symbol: "*",
+		Apply:  func(left, right int) (int, bool) { return left * right, true },
+		Undo: func(result, right int) ([]int, bool) {
+			if right == 0 {
+				return nil, result != 0
+			}
+			if result%right != 0 {
+				return nil, true
+			}
+			return []int{result / right}, true
+		},
+	})
+	Register(Operator{
+		Symbol: "/",
+		Apply: func(left, right int) (int, bool) {
+			if right == 0 || left%right != 0 {
+				return 0, false
+			}
+			return left / right, true
+		},
+		Undo: func(result, right int) ([]int, bool) {
+			if right == 0 {
+				return nil, true
+			}
+			return []int{result * right}, true
+		},
+	})
+	Register(Operator{
+		Symbol: "%",
+		Apply: func(left, right int) (int, bool) {
+			if right == 0 {
+				return 0, false
+			}
+			return left % right, true
+		},
+		Undo: func(int, int) ([]int, bool) { return nil, false },
+	})
+	Register(Operator{
+		Symbol: "^",
+		Apply: func(left, right int) (int, bool) {
+			if right < 0 {
+				return 0, false
+			}
+			return power(left, right)
+		},
+		Undo: func(result, right int) ([]int, bool) {
+			if right == 0 {
+				return nil, result != 1
+			}
+			if right < 0 {
+				return nil, true
+			}
+			return deriveRoots(result, right), true
+		},
+	})
+	Register(Operator{
+		Symbol: "||",
+		Apply: func(left, right int) (int, bool) {
+			if left < 0 || right < 0 {
+				return 0, false
+			}
+			return concat(left, right), true
+		},
+		Undo: func(result, right int) ([]int, bool) {
+			if result < 0 || right < 0 {
+				return nil, true
+			}
+			mul := shared.Pow(10, shared.DigitLength(right))
+			if result%mul != right {
+				return nil, true
+			}
+			return []int{result / mul}, true
+		},
+	})
+}
+
+// power raises base to a non-negative exponent. It's not ok if the result overflows, which stops
+// the loop after at most 63 multiplications.
+func power(base, exponent int) (int, bool) {
+	switch base {
+	case 0:
+		return shared.Or(exponent == 0, 1, 0), true
+	case 1:
+		return 1, true
+	case -1:
+		return shared.Or(exponent%2 == 0, 1, -1), true
+	}
+	result := 1
+	for range exponent {
+		if shared.Abs(result) > math.MaxInt/shared.Abs(base) {
+			return 0, false
+		}
+		result *= base
+	}
+	return result, true
+}
+
+// deriveRoots derives the integers whose power of exponent is value.
+func deriveRoots(value, exponent int) []int {
+	root := int(math.Round(math.Pow(math.Abs(float64(value)), 1/float64(exponent))))
+	var roots []int
+	for _, each := range []int{root, -root} {
+		if result, ok := power(each, exponent); ok && result == value &&
+			(len(roots) == 0 || roots[0] != each) {
+			roots = append(roots, each)
+		}
+	}
+	return roots
+}
+
+// Solution is an equation that produces the test value.
+type Solution struct {
+	Value int
+	// Expression is the equation with the operators that produce the value, e.g. "81 + 40 * 27".
+	// It's empty when there's no such combination of operators.
+	Expression string
+}
+
+// Solve finds the operators that produce the test value for each equation.
+func Solve(lines []string, operators []Operator) []Solution {
 	dtos := toCalibrationDtos(lines)
+	solutions := make([]Solution, 0, len(dtos))
+	for _, each := range dtos {
+		s := newSolver(each, operators)
+		solution := Solution{Value: each.sum}
+		if ops := s.solve(len(each.parts)-1, each.sum); ops != nil {
+			solution.Expression = formatExpression(each.parts, ops)
+		}
+		shared.Logger.Debug("Equation solved.", "solution", solution)
+		solutions = append(solutions, solution)
+	}
+	return solutions
+}
+
+// DeriveCalibrationSum sums the test values of the equations that the operators can produce.
+func DeriveCalibrationSum(lines []string, operators []Operator) int {
 	total := 0
 	sumTotal := 0
-	permGen := newPermutationGenerator()
-	for _, each := range dtos {
-		sumTotal += each.sum
-		if isValid(each, withConcat, permGen) {
-			total += each.sum
+	for _, each := range Solve(lines, operators) {
+		sumTotal += each.Value
+		if each.Expression != "" {
+			total += each.Value
 		}
 	}
-	shared.Logger.Info("Sums.", "total", sumTotal, "valid", total, "with concat", withConcat)
+	shared.Logger.Info(
+		"Sums.",
+		"total",
+		sumTotal,
+		"valid",
+		total,
+		"operators",
+		gent.Map(operators, func(op Operator) string { return op.Symbol }),
+	)
 	return total
 }
 
+func formatExpression(parts []int, ops []Operator) string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(parts[0]))
+	for i, each := range ops {
+		fmt.Fprintf(&b, " %s %d", each.Symbol, parts[i+1])
+	}
+	return b.String()
+}
+
+type solver struct {
+	parts     []int
+	operators []Operator
+	// reachable[i] are the values that parts[:i+1] can produce. Only filled for the operators
+	// that can't be undone.
+	reachable map[int]map[int]bool
+}
+
+func newSolver(dto calibrationDto, operators []Operator) *solver {
+	return &solver{
+		parts:     dto.parts,
+		operators: operators,
+		reachable: map[int]map[int]bool{},
+	}
+}
+
+// solve searches from right to left: it undoes the operator between parts[index-1] and
+// parts[index] from the target and continues with the left operand as the new target. It returns
+// the operators that turn parts[:index+1] into target, or nil if there are none.
+func (v *solver) solve(index, target int) []Operator {
+	if index == 0 {
+		if v.parts[0] == target {
+			return []Operator{}
+		}
+		return nil
+	}
+	right := v.parts[index]
+	for _, op := range v.operators {
+		lefts, ok := op.Undo(target, right)
+		if !ok {
+			lefts = v.findForward(index-1, op, target, right)
+		}
+		for _, left := range lefts {
+			if ops := v.solve(index-1, left); ops != nil {
+				return append(ops, op)
+			}
+		}
+	}
+	return nil
+}
+
+// findForward finds the values that parts[:index+1] can produce and that op turns into target.
+// They're sorted so that the solution doesn't depend on the map's order.
+func (v *solver) findForward(index int, op Operator, target, right int) []int {
+	var lefts []int
+	for each := range v.deriveReachable(index) {
+		if result, ok := op.Apply(each, right); ok && result == target {
+			lefts = append(lefts, each)
+		}
+	}
+	slices.Sort(lefts)
+	return lefts
+}
+
+func (v *solver) deriveReachable(index int) map[int]bool {
+	if reachable, ok := v.reachable[index]; ok {
+		return reachable
+	}
+	reachable := map[int]bool{}
+	if index == 0 {
+		reachable[v.parts[0]] = true
+	} else {
+		for each := range v.deriveReachable(index - 1) {
+			for _, op := range v.operators {
+				if result, ok := op.Apply(each, v.parts[index]); ok {
+					reachable[result] = true
+				}
+			}
+		}
+	}
+	v.reachable[index] = reachable
+	return reachable
+}
+
 type calibrationDto struct {
 	sum   int
 	parts []int
@@ -55,96 +328,8 @@ func toCalibrationDtos(lines []string) []calibrationDto {
 		})
 }
 
-func isValid(dto calibrationDto, withConcat bool, permGen *permutationGenerator) bool {
-	if len(dto.parts) == 1 {
-		return dto.sum == dto.parts[0]
-	}
-	base := 2
-	if withConcat {
-		base = 3
-	}
-	for _, each := range permGen.generate(permutationSpec{length: len(dto.parts) - 1, base: base}) {
-		sum := deriveSum(dto, each)
-		if sum == dto.sum {
-			return true
-		}
-	}
-	return false
-}
-
-func deriveSum(dto calibrationDto, operators []int) int {
-	res, tail := dto.parts[0], dto.parts[1:]
-	for i, each := range tail {
-		if res > dto.sum {
-			return res
-		}
-		switch operators[i] {
-		case 0:
-			res += each
-		case 1:
-			res *= each
-		case 2:
-			res = concat(res, each)
-		default:
-			panic(fmt.Sprintf("Unknown operator: %d.", operators[i]))
-		}
-	}
-	return res
-}
-
-func generatePermutations(length, base int) [][]int {
-	strs := generateZeroPaddedNumericStrings(length, base)
-	return gent.Map(
-		strs,
-		func(s string) []int {
-			nums, err := shared.ToInts(strings.Split(s, ""))
-			if err != nil {
-				panic(err)
-			}
-			return nums
-		})
-}
-
 func concat(a, b int) int {
 	length := shared.DigitLength(b)
 	mul := shared.Pow(10, length)
 	return a*mul + b
 }
-
-func generateZeroPaddedNumericStrings(length, base int) []string {
-	max := shared.Pow(base, length)
-	strs := make([]string, 0, max)
-	for i := range max {
-		s := strconv.FormatInt(int64(i), base)
-		missing := length - len(s)
-		if missing > 0 {
-			s = strings.Repeat("0", missing) + s
-		}
-		strs = append(strs, s)
-	}
-	return strs
-}
-
-type permutationSpec struct {
-	length int
-	base   int
-}
-
-type permutationGenerator struct {
-	m map[permutationSpec][][]int
-}
-
-func newPermutationGenerator() *permutationGenerator {
-	return &permutationGenerator{
-		m: map[permutationSpec][][]int{},
-	}
-}
-
-func (v *permutationGenerator) generate(spec permutationSpec) [][]int {
-	if existing, exists := v.m[spec]; exists {
-		return existing
-	}
-	perm := generatePermutations(spec.length, spec.base)
-	v.m[spec] = perm
-	return perm
-}
@@ -9,16 +9,83 @@ import (
 )
 
 func TestDeriveCalibrationSum(t *testing.T) {
-	run := func(name string, withConcat bool, lines []string, expected int) {
+	run := func(name string, symbols []string, lines []string, expected int) {
 		t.Run(name, func(t *testing.T) {
 			shared.InitTestLogging(t)
-			require.Equal(t, expected, DeriveCalibrationSum(lines, withConcat))
+			req := require.New(t)
+			operators, err := LookupOperators(symbols...)
+			req.NoError(err)
+
+			// EXERCISE & VERIFY
+			req.Equal(expected, DeriveCalibrationSum(lines, operators))
+		})
+	}
+
+	run("empty", []string{"+", "*"}, []string{}, 0)
+	run("example without concat", []string{"+", "*"}, getExampleLines(), 3749)
+	run("example with concat", []string{"+", "*", "||"}, getExampleLines(), 11387)
+}
+
+func TestSolve(t *testing.T) {
+	run := func(name string, symbols []string, line string, expected string) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			operators, err := LookupOperators(symbols...)
+			req.NoError(err)
+
+			// EXERCISE
+			solutions := Solve([]string{line}, operators)
+
+			// VERIFY
+			req.Len(solutions, 1)
+			req.Equal(expected, solutions[0].Expression)
 		})
 	}
 
-	run("empty", false, []string{}, 0)
-	run("example without concat", false, getExampleLines(), 3749)
-	run("example with concat", true, getExampleLines(), 11387)
+	run("add", []string{"+", "*"}, "29: 10 19", "10 + 19")
+	run("multiply", []string{"+", "*"}, "190: 10 19", "10 * 19")
+	run("mixed", []string{"+", "*"}, "292: 11 6 16 20", "11 + 6 * 16 + 20")
+	run("no solution", []string{"+", "*"}, "156: 15 6", "")
+	run("concat", []string{"+", "*", "||"}, "7290: 6 8 6 15", "6 * 8 || 6 * 15")
+	run("subtract", []string{"+", "-"}, "3: 10 4 3", "10 - 4 - 3")
+	run("exact division", []string{"/"}, "5: 30 2 3", "30 / 2 / 3")
+	run("inexact division", []string{"/"}, "5: 31 2 3", "")
+	run("power", []string{"+", "^"}, "81: 1 2 4", "1 + 2 ^ 4")
+	run("negative power", []string{"-", "^"}, "16: 1 3 4", "1 - 3 ^ 4")
+	run("modulo", []string{"+", "%"}, "2: 10 7 5", "10 + 7 % 5")
+	run("multiply by zero", []string{"-", "*"}, "0: 5 3 0", "5 - 3 * 0")
+	run("power of zero", []string{"%", "^"}, "1: 5 3 0", "5 % 3 ^ 0")
+	// Powers this large overflow after a few dozen multiplications.
+	run("huge power", []string{"*", "^"}, "0: 3 100000000000 0", "3 * 100000000000 * 0")
+	run("huge power of one", []string{"+", "^"}, "1: 1 100000000000", "1 ^ 100000000000")
+	run("overflowing power", []string{"+", "^"}, "2: 2 64", "")
+}
+
+func TestSolveIsDeterministic(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	operators, err := LookupOperators("+", "*", "%")
+	req.NoError(err)
+
+	// Both 3 + 4 and 3 * 4 leave 2 modulo 5. The smaller left operand is picked.
+	for range 50 {
+		// EXERCISE
+		solutions := Solve([]string{"2: 3 4 5"}, operators)
+
+		// VERIFY
+		req.Equal("3 + 4 % 5", solutions[0].Expression)
+	}
+}
+
+func TestLookupOperators(t *testing.T) {
+	req := require.New(t)
+	operators, err := LookupOperators("+", "||")
+	req.NoError(err)
+	req.Equal([]string{"+", "||"}, []string{operators[0].Symbol, operators[1].Symbol})
+
+	_, err = LookupOperators("+", "&")
+	req.ErrorContains(err, "unknown operator: &")
 }
 
 func getExampleLines() []string {
@@ -35,44 +102,6 @@ func getExampleLines() []string {
 	}
 }
 
-func TestGeneratePermutations(t *testing.T) {
-	run := func(length, base int, expected [][]int) {
-		t.Run(fmt.Sprintf("%d-%d", length, base), func(t *testing.T) {
-			shared.InitTestLogging(t)
-			require.Equal(t, expected, generatePermutations(length, base))
-		})
-	}
-
-	run(1, 2, [][]int{{0}, {1}})
-	run(2, 2, [][]int{{0, 0}, {0, 1}, {1, 0}, {1, 1}})
-	run(3, 2, [][]int{
-		{0, 0, 0},
-		{0, 0, 1},
-		{0, 1, 0},
-		{0, 1, 1},
-		{1, 0, 0},
-		{1, 0, 1},
-		{1, 1, 0},
-		{1, 1, 1},
-	})
-
-	run(1, 3, [][]int{{0}, {1}, {2}})
-	run(
-		2,
-		3,
-		[][]int{
-			{0, 0},
-			{0, 1},
-			{0, 2},
-			{1, 0},
-			{1, 1},
-			{1, 2},
-			{2, 0},
-			{2, 1},
-			{2, 2},
-		})
-}
-
 func TestConcat(t *testing.T) {
 	run := func(expected, first, second int) {
 		name := fmt.Sprintf("%d=%d+%d", expected, first, second)
@@ -1,10 +1,11 @@
 package aoc2409
 
 import (
-	"strconv"
+	"image/color"
 	"strings"
 
 	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/render"
 	"github.com/denarced/gent"
 )
 
@@ -162,16 +163,28 @@ func slipIn(org []atom, piece atom, i int) []atom {
 	return evolved
 }
 
-func visualize(org []atom) string {
-	v := ""
+// visualize draws the disk on a single row, each block of a file as the last digit of its ID.
+// Files take turns in colours so that neighbours with the same last digit can be told apart.
+func visualize(org []atom) *render.Scene {
+	var width int
+	for _, each := range org {
+		width += each.width
+	}
+	colors := []color.RGBA{render.Green, render.Yellow, render.Blue, render.Red}
+	var layers []render.Layer
+	x := 0
 	for _, each := range org {
 		if each.file {
-			v += strings.Repeat(strconv.FormatInt(int64(each.id), 10), each.width)
-		} else {
-			v += strings.Repeat(".", each.width)
+			locs := make([]shared.Loc, each.width)
+			for i := range locs {
+				locs[i] = shared.Loc{X: x + i, Y: 0}
+			}
+			char := rune('0' + each.id%10)
+			layers = append(layers, render.Mark(locs, char, colors[each.id%len(colors)]))
 		}
+		x += each.width
 	}
-	return v
+	return render.NewScene(render.Blank(width, 1), layers...)
 }
 
 func toAtoms(s string) []atom {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/render"
 	"github.com/stretchr/testify/require"
 )
 
@@ -68,3 +69,24 @@ func TestCountDefragmentedChecksum(t *testing.T) {
 	run("18304", sum("02222111"))
 	run("2333133121414131402", 2858)
 }
+
+func TestVisualize(t *testing.T) {
+	run := func(s, expected string) {
+		t.Run(s, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+
+			// EXERCISE
+			scene := visualize(defrag(toAtoms(s)))
+
+			// VERIFY
+			req.Equal([]string{expected}, render.Lines(scene))
+			cells := scene.Cells()[0]
+			req.NotEqual(cells[0].Color, cells[2].Color)
+		})
+	}
+
+	run("2333133121414131402", "00992111777.44.333....5555.6666.....8888..")
+	// File 10 is drawn as 0.
+	run("101010101010101010102", "012345678900")
+}
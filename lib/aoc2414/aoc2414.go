@@ -2,16 +2,24 @@ package aoc2414
 
 import (
 	"fmt"
-	"sort"
 	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/render"
 )
 
+// FrameCb receives the robots on a board at each step when set. Each location has the number of
+// robots in it, or "." if there are none.
+var FrameCb func(brd *shared.Board)
+
 func DeriveSafetyFactor(lines []string, width, height, steps int) int {
 	ints := parseLines(lines)
+	if FrameCb != nil {
+		for i := range steps + 1 {
+			FrameCb(createFrame(ints, width, height, i))
+		}
+	}
 	quadrants := []int{0, 0, 0, 0, -999_999_999_999}
 	for _, each := range ints {
 		x, y := deriveCoordinates(each, width, height, steps)
@@ -21,75 +29,25 @@ func DeriveSafetyFactor(lines []string, width, height, steps int) int {
 	return multiply(quadrants[:4])
 }
 
+// minimumConfidence is the confidence above which a detection is a picture, see Detection.
+const minimumConfidence = 5
+
+// FindChristmasTree finds the first step after the start where the robots draw a picture, or -1
+// if there's none. A picture at the start is found again when the robots have cycled back.
 func FindChristmasTree(lines []string, width, height int) int {
-	ints := parseLines(lines)
-	minimumNeighbourCount := 20
-	// Based on quick testing with hyperfine, there's not much benefit in having more threads than
-	// 8. There's some but not much so using a conservative value here.
-	threadCount := 8
-	feedCh := make(chan int, threadCount)
-	resultCh := make(chan int)
-	foundCh := make(chan int)
-	found := -1
-	go func() {
-		for each := range resultCh {
-			if found == -1 {
-				found = each
-			} else {
-				found = shared.Min(found, each)
-			}
-		}
-		foundCh <- found
-	}()
-	var wg sync.WaitGroup
-	for range threadCount {
-		wg.Add(1)
-		go countNeighboursAtStep(ints, width, height, minimumNeighbourCount, feedCh, resultCh, &wg)
+	detection, err := DetectPattern(lines, width, height, Variance)
+	if err != nil {
+		shared.Logger.Error("Failed to detect pattern.", "err", err)
+		return -1
 	}
-	for i := 1; i < 1_000_000 && found < 0; i++ {
-		feedCh <- i
+	if detection.Confidence <= minimumConfidence {
+		shared.Logger.Info("No picture, only noise.", "confidence", detection.Confidence)
+		return -1
 	}
-	close(feedCh)
-	wg.Wait()
-	close(resultCh)
-	return <-foundCh
-}
-
-func countNeighboursAtStep(
-	ints [][]int,
-	width,
-	height,
-	minimumNeighbourCount int,
-	in <-chan int,
-	out chan<- int,
-	wg *sync.WaitGroup,
-) {
-	defer wg.Done()
-	for i := range in {
-		yToCoords := make(map[int][]int, height)
-		skip := true
-		for j, each := range ints {
-			x, y := deriveCoordinates(each, width, height, i)
-			xs := yToCoords[y]
-			if xs == nil {
-				xs = make([]int, 0, 20)
-			}
-			xs = append(xs, x)
-			yToCoords[y] = xs
-			if j >= minimumNeighbourCount && minimumNeighbourCount <= len(xs) {
-				skip = false
-			}
-		}
-
-		if skip {
-			continue
-		}
-
-		count := countNeighbours(yToCoords, minimumNeighbourCount)
-		if count >= minimumNeighbourCount {
-			out <- i
-		}
+	if detection.Step == 0 {
+		return detection.Period
 	}
+	return detection.Step
 }
 
 func multiply(values []int) int {
@@ -172,43 +130,15 @@ func splitPair(s, sep string) (first string, second string) {
 	return
 }
 
-func countNeighbours(m map[int][]int, minimum int) int {
-	highest := 0
-	for _, xSet := range m {
-		if len(xSet) < minimum {
-			continue
-		}
-		sort.Ints(xSet)
-		previous := -1
-		count := 0
-		for _, each := range xSet {
-			if previous == -1 {
-				previous = each
-				continue
-			}
-			if previous+1 == each {
-				count++
-			}
-			previous = each
-		}
-		if count >= minimum {
-			return count
-		}
-		highest = shared.Max(highest, count)
-	}
-	return highest
-}
-
-func printBoard(coords [][]int, width, height int) {
-	board := []string{}
-	for range height {
-		board = append(board, strings.Repeat(" ", width))
+func createFrame(ints [][]int, width, height, steps int) *shared.Board {
+	counts := map[shared.Loc]int{}
+	for _, each := range ints {
+		x, y := deriveCoordinates(each, width, height, steps)
+		counts[shared.Loc{X: x, Y: height - 1 - y}]++
 	}
-	for _, each := range coords {
-		line := board[each[1]]
-		x := each[0]
-		line = line[:x] + "." + line[x+1:]
-		board[each[1]] = line
+	brd := render.Blank(width, height)
+	for loc, count := range counts {
+		brd.Set(loc, rune('0'+min(count, 9)))
 	}
-	fmt.Println(strings.Join(board, "\n") + "\n")
+	return brd
 }
@@ -2,6 +2,8 @@ package aoc2414
 
 import (
 	"fmt"
+	"math"
+	"math/rand/v2"
 	"testing"
 
 	"github.com/denarced/advent-of-code/shared"
@@ -70,3 +72,96 @@ func TestDeriveCoordinates(t *testing.T) {
 		1,
 		10, 6)
 }
+
+// generateRobots generates robots that draw a filled rectangle at the step. Noise robots are
+// scattered around.
+func generateRobots(width, height, step, pictureCount, noiseCount int) []string {
+	rng := rand.New(rand.NewPCG(uint64(step), 1))
+	var lines []string
+	add := func(x, y int) {
+		dx := rng.IntN(2*width) - width
+		dy := rng.IntN(2*height) - height
+		px := shared.ModForIndex(x-step*dx, width)
+		py := shared.ModForIndex(y-step*dy, height)
+		lines = append(lines, fmt.Sprintf("p=%d,%d v=%d,%d", px, py, dx, dy))
+	}
+	side := int(math.Sqrt(float64(pictureCount)))
+	for i := range pictureCount {
+		add(width/3+i%side, height/3+i/side)
+	}
+	for range noiseCount {
+		add(rng.IntN(width), rng.IntN(height))
+	}
+	return lines
+}
+
+func TestDetectPattern(t *testing.T) {
+	run := func(scorer Scorer, step int) {
+		t.Run(fmt.Sprintf("%s %d", scorer, step), func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			lines := generateRobots(101, 103, step, 300, 200)
+
+			// EXERCISE
+			detection, err := DetectPattern(lines, 101, 103, scorer)
+
+			// VERIFY
+			req.NoError(err)
+			req.Equal(step, detection.Step)
+			req.Greater(detection.Confidence, 5.0)
+			req.Len(detection.Frame, 103)
+			req.Equal("1111", detection.Frame[103/3][101/3:101/3+4])
+		})
+	}
+
+	for _, each := range []Scorer{Variance, Entropy} {
+		run(each, 0)
+		run(each, 6_512)
+		run(each, 101*103-1)
+	}
+}
+
+func TestDetectPatternInNoise(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	lines := generateRobots(101, 103, 77, 0, 500)
+
+	for _, each := range []Scorer{Variance, Entropy} {
+		// EXERCISE
+		detection, err := DetectPattern(lines, 101, 103, each)
+
+		// VERIFY
+		req.NoError(err)
+		req.Less(detection.Confidence, 5.0)
+	}
+	req.Equal(-1, FindChristmasTree(lines, 101, 103))
+}
+
+func TestFindChristmasTree(t *testing.T) {
+	run := func(width, height, step, expected int) {
+		t.Run(fmt.Sprint(width, height, step), func(t *testing.T) {
+			shared.InitTestLogging(t)
+			lines := generateRobots(width, height, step, 300, 200)
+
+			// EXERCISE
+			found := FindChristmasTree(lines, width, height)
+
+			// VERIFY
+			require.Equal(t, expected, found)
+		})
+	}
+
+	run(101, 103, 6_512, 6_512)
+	// The puzzle counts the seconds that elapse so the start doesn't count.
+	run(101, 103, 0, 101*103)
+	// The robots repeat every lcm(100, 102) steps.
+	run(100, 102, 0, 5_100)
+}
+
+func TestDetectPatternErrors(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	_, err := DetectPattern([]string{}, 11, 7, Variance)
+	req.EqualError(err, "no robots")
+	req.Equal(-1, FindChristmasTree([]string{}, 11, 7))
+}
@@ -0,0 +1,141 @@
+package aoc2414
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/denarced/advent-of-code/shared"
+)
+
+// Scorer scores how scattered robots are along an axis. The lower the score, the more clustered.
+type Scorer int
+
+const (
+	// Variance is the variance of the coordinates.
+	Variance Scorer = iota
+	// Entropy is the Shannon entropy of the coordinates' histogram.
+	Entropy
+)
+
+func (v Scorer) String() string {
+	switch v {
+	case Variance:
+		return "variance"
+	case Entropy:
+		return "entropy"
+	}
+	return "unknown"
+}
+
+// Detection is the step where the robots are the most clustered.
+type Detection struct {
+	Step int
+	// Period is how often the robots repeat their positions, the least common multiple of the
+	// width and the height.
+	Period int
+	// Confidence is how many standard deviations the best score is below the mean of the scores,
+	// the lower of the two axes. Even random noise has a best step around 2 or 3 deviations below
+	// the mean, a picture is well above 5.
+	Confidence float64
+	// Frame is the robots at the step, see createFrame.
+	Frame []string
+}
+
+// DetectPattern finds the step where the robots are most clustered. X positions repeat every width
+// steps and y positions every height steps so each axis is scored separately and the best steps
+// are combined with the Chinese remainder theorem.
+func DetectPattern(lines []string, width, height int, scorer Scorer) (Detection, error) {
+	ints := parseLines(lines)
+	if len(ints) == 0 {
+		return Detection{}, fmt.Errorf("no robots")
+	}
+	shared.Logger.Info(
+		"Detect pattern.",
+		"robots", len(ints),
+		"width", width,
+		"height", height,
+		"scorer", scorer)
+	xStep, xConfidence := findBestStep(ints, width, 0, scorer)
+	yStep, yConfidence := findBestStep(ints, height, 1, scorer)
+	combined, ok := shared.CombineCongruences(
+		shared.Congruence{Remainder: xStep, Modulus: width},
+		shared.Congruence{Remainder: yStep, Modulus: height})
+	if !ok {
+		return Detection{}, fmt.Errorf(
+			"x step %d (mod %d) and y step %d (mod %d) never coincide",
+			xStep,
+			width,
+			yStep,
+			height)
+	}
+	detection := Detection{
+		Step:       combined.Remainder,
+		Period:     combined.Modulus,
+		Confidence: min(xConfidence, yConfidence),
+		Frame:      createFrame(ints, width, height, combined.Remainder).GetLines(),
+	}
+	shared.Logger.Info(
+		"Pattern detected.",
+		"step", detection.Step,
+		"x step", xStep,
+		"y step", yStep,
+		"confidence", detection.Confidence)
+	return detection, nil
+}
+
+// findBestStep finds the step within the period where the axis (0 for x, 1 for y) scores lowest.
+func findBestStep(ints [][]int, period, axis int, scorer Scorer) (step int, confidence float64) {
+	scores := make([]float64, period)
+	coordinates := make([]int, len(ints))
+	for i := range period {
+		for j, each := range ints {
+			coordinates[j] = shared.ModForIndex(each[axis]+i*each[axis+2], period)
+		}
+		scores[i] = score(coordinates, period, scorer)
+		if scores[i] < scores[step] {
+			step = i
+		}
+	}
+	mean, deviation := deriveMeanAndDeviation(scores)
+	if deviation == 0 {
+		return step, 0
+	}
+	return step, (mean - scores[step]) / deviation
+}
+
+func score(coordinates []int, period int, scorer Scorer) float64 {
+	switch scorer {
+	case Variance:
+		values := make([]float64, len(coordinates))
+		for i, each := range coordinates {
+			values[i] = float64(each)
+		}
+		_, deviation := deriveMeanAndDeviation(values)
+		return deviation * deviation
+	case Entropy:
+		counts := make([]int, period)
+		for _, each := range coordinates {
+			counts[each]++
+		}
+		entropy := 0.0
+		for _, each := range counts {
+			if each > 0 {
+				p := float64(each) / float64(len(coordinates))
+				entropy -= p * math.Log2(p)
+			}
+		}
+		return entropy
+	}
+	panic(fmt.Sprintf("Unknown scorer: %d.", scorer))
+}
+
+func deriveMeanAndDeviation(values []float64) (mean, deviation float64) {
+	for _, each := range values {
+		mean += each
+	}
+	mean /= float64(len(values))
+	for _, each := range values {
+		deviation += (each - mean) * (each - mean)
+	}
+	return mean, math.Sqrt(deviation / float64(len(values)))
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/denarced/gent"
 )
 
+// FrameCb receives the board after each move when set.
+var FrameCb func(brd *shared.Board)
+
 func CountCoordinateSum(lines []string, doubled bool) int {
 	if len(lines) == 0 {
 		return 0
@@ -116,42 +119,53 @@ func charToLoc(c rune) shared.Loc {
 
 func walk(brd *shared.Board, directions []rune, doubled bool) {
 	robotLoc := findRobot(brd)
+	recordFrame(brd)
 	for _, d := range directions {
-		shared.Logger.Info("Move robot.", "direction", d, "robot", robotLoc)
-		loc := charToLoc(d)
-		to := robotLoc.Delta(loc)
-		c, ok := brd.Get(to)
-		shared.Logger.Debug(
-			"About to move robot.",
-			"from", robotLoc,
-			"to", to,
-			"c", string(c),
-			"ok", ok,
-			"delta", loc,
-			"dir", string(d),
-		)
-		if !ok {
-			continue
-		}
-		if c == '.' {
-			swap(brd, robotLoc, to)
-			robotLoc = to
-			continue
-		}
-		if c == '#' {
-			continue
+		robotLoc = step(brd, robotLoc, d, doubled)
+		recordFrame(brd)
+	}
+}
+
+func step(brd *shared.Board, robotLoc shared.Loc, d rune, doubled bool) shared.Loc {
+	shared.Logger.Info("Move robot.", "direction", d, "robot", robotLoc)
+	loc := charToLoc(d)
+	to := robotLoc.Delta(loc)
+	c, ok := brd.Get(to)
+	shared.Logger.Debug(
+		"About to move robot.",
+		"from", robotLoc,
+		"to", to,
+		"c", string(c),
+		"ok", ok,
+		"delta", loc,
+		"dir", string(d),
+	)
+	if !ok {
+		return robotLoc
+	}
+	if c == '.' {
+		swap(brd, robotLoc, to)
+		return to
+	}
+	if c == '#' {
+		return robotLoc
+	}
+	if isBox(c, doubled) {
+		if !doubled {
+			return moveRobot(brd, robotLoc, d)
 		}
-		if isBox(c, doubled) {
-			if !doubled {
-				robotLoc = moveRobot(brd, robotLoc, d)
-			} else {
-				moves := deriveMovedBoxes(brd, robotLoc, d)
-				if moves != nil {
-					robotLoc = moveRobotAndBoxes(brd, moves)
-				}
-			}
+		moves := deriveMovedBoxes(brd, robotLoc, d)
+		if moves != nil {
+			return moveRobotAndBoxes(brd, moves)
 		}
 	}
+	return robotLoc
+}
+
+func recordFrame(brd *shared.Board) {
+	if FrameCb != nil {
+		FrameCb(brd)
+	}
 }
 
 func moveRobotAndBoxes(brd *shared.Board, moves []shared.Pair[shared.Loc]) shared.Loc {
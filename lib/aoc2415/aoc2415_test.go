@@ -646,3 +646,27 @@ func TestCountGps(t *testing.T) {
 		},
 		9021)
 }
+
+func TestCountCoordinateSumFrames(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	var frames []string
+	FrameCb = func(brd *shared.Board) {
+		frames = append(frames, strings.Join(brd.GetLines(), "/"))
+	}
+	defer func() {
+		FrameCb = nil
+	}()
+
+	// EXERCISE
+	CountCoordinateSum([]string{"#####", "#@O.#", "#####", "", ">>"}, false)
+
+	// VERIFY
+	req.Equal(
+		[]string{
+			"#####/#@O.#/#####",
+			"#####/#.@O#/#####",
+			"#####/#.@O#/#####",
+		},
+		frames)
+}
@@ -0,0 +1,121 @@
+package aoc2415
+
+import (
+	"fmt"
+
+	"github.com/denarced/advent-of-code/shared"
+)
+
+// Warehouse is played one move at a time, e.g. interactively. Moves can be undone and redone.
+type Warehouse struct {
+	original []string
+	doubled  bool
+	brd      *shared.Board
+	robot    shared.Loc
+	moves    []rune
+	// history has the board before each move of moves.
+	history []*shared.Board
+	redo    []rune
+}
+
+// NewWarehouse creates a warehouse from puzzle lines. Moves in the lines are ignored.
+func NewWarehouse(lines []string, doubled bool) (*Warehouse, error) {
+	boardLines, _ := splitLines(lines)
+	if len(boardLines) == 0 {
+		return nil, fmt.Errorf("no warehouse in the lines")
+	}
+	original := boardLines
+	if doubled {
+		boardLines = double(boardLines)
+	}
+	brd := shared.NewBoard(boardLines)
+	robots := 0
+	brd.Iter(func(_ shared.Loc, c rune) bool {
+		if c == '@' {
+			robots++
+		}
+		return true
+	})
+	if robots != 1 {
+		return nil, fmt.Errorf("expected one robot, found %d", robots)
+	}
+	return &Warehouse{
+		original: original,
+		doubled:  doubled,
+		brd:      brd,
+		robot:    findRobot(brd),
+	}, nil
+}
+
+// Move moves the robot, pushing boxes if it can. It clears the moves that could be redone.
+func (v *Warehouse) Move(d rune) error {
+	if !isDirection(d) {
+		return fmt.Errorf("not a move: %c", d)
+	}
+	v.redo = nil
+	v.move(d)
+	return nil
+}
+
+func (v *Warehouse) move(d rune) {
+	v.history = append(v.history, v.brd.Copy())
+	v.moves = append(v.moves, d)
+	v.robot = step(v.brd, v.robot, d, v.doubled)
+}
+
+// Undo undoes the latest move. It's not ok if there's nothing to undo.
+func (v *Warehouse) Undo() (ok bool) {
+	last := len(v.moves) - 1
+	if last < 0 {
+		return false
+	}
+	v.redo = append(v.redo, v.moves[last])
+	v.brd = v.history[last]
+	v.robot = findRobot(v.brd)
+	v.moves = v.moves[:last]
+	v.history = v.history[:last]
+	return true
+}
+
+// Redo redoes the latest undone move. It's not ok if there's nothing to redo.
+func (v *Warehouse) Redo() (ok bool) {
+	last := len(v.redo) - 1
+	if last < 0 {
+		return false
+	}
+	v.move(v.redo[last])
+	v.redo = v.redo[:last]
+	return true
+}
+
+// Gps is the sum of the boxes' GPS coordinates.
+func (v *Warehouse) Gps() int {
+	return countGps(v.brd, v.doubled)
+}
+
+// Board is the current state of the warehouse. Don't modify it.
+func (v *Warehouse) Board() *shared.Board {
+	return v.brd
+}
+
+// Moves is the moves so far as a puzzle move string.
+func (v *Warehouse) Moves() string {
+	return string(v.moves)
+}
+
+// Export creates puzzle lines from the original warehouse and the moves so far. The warehouse isn't
+// doubled, doubling is part of solving the puzzle.
+func (v *Warehouse) Export() []string {
+	lines := append([]string{}, v.original...)
+	lines = append(lines, "")
+	moves := v.Moves()
+	// The puzzle splits the moves to lines of 1000.
+	for len(moves) > 1000 {
+		lines = append(lines, moves[:1000])
+		moves = moves[1000:]
+	}
+	if moves != "" {
+		lines = append(lines, moves)
+	}
+	return lines
+}
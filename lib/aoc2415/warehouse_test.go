@@ -0,0 +1,90 @@
+package aoc2415
+
+import (
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func getWideExample() []string {
+	return []string{
+		"#######",
+		"#...#.#",
+		"#.....#",
+		"#..OO@#",
+		"#..O..#",
+		"#.....#",
+		"#######",
+	}
+}
+
+func TestWarehouse(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	w, err := NewWarehouse(getWideExample(), true)
+	req.NoError(err)
+	initial := w.Board().GetLines()
+
+	// EXERCISE
+	for _, each := range "<vv<<^^<<^^" {
+		req.NoError(w.Move(each))
+	}
+
+	// VERIFY
+	req.Equal(
+		[]string{
+			"##############",
+			"##...[].##..##",
+			"##...@.[]...##",
+			"##....[]....##",
+			"##..........##",
+			"##..........##",
+			"##############",
+		},
+		w.Board().GetLines())
+	req.Equal(105+207+306, w.Gps())
+	req.Equal("<vv<<^^<<^^", w.Moves())
+
+	// Undo everything and redo a part.
+	for w.Undo() {
+	}
+	req.Equal(initial, w.Board().GetLines())
+	req.Empty(w.Moves())
+	req.True(w.Redo())
+	req.True(w.Redo())
+	req.Equal("<v", w.Moves())
+	req.NoError(w.Move('>'))
+	req.False(w.Redo(), "a new move clears redo")
+	req.Equal("<v>", w.Moves())
+}
+
+func TestWarehouseExport(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	w, err := NewWarehouse(getWideExample(), true)
+	req.NoError(err)
+	for _, each := range "<vv<<^^<<^^" {
+		req.NoError(w.Move(each))
+	}
+
+	// EXERCISE
+	lines := w.Export()
+
+	// VERIFY
+	req.Equal(append(getWideExample(), "", "<vv<<^^<<^^"), lines)
+	req.Equal(w.Gps(), CountCoordinateSum(lines, true))
+}
+
+func TestNewWarehouseErrors(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	_, err := NewWarehouse([]string{}, false)
+	req.EqualError(err, "no warehouse in the lines")
+	_, err = NewWarehouse([]string{"#@.@#"}, false)
+	req.EqualError(err, "expected one robot, found 2")
+
+	w, err := NewWarehouse([]string{"#@.#"}, false)
+	req.NoError(err)
+	req.EqualError(w.Move('x'), "not a move: x")
+}
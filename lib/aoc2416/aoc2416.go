@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/render"
 	"github.com/denarced/gent"
 )
 
@@ -222,15 +223,20 @@ func draw(lines []string, step *shared.Link[shared.Loc]) {
 	dirp := fmt.Sprintf("/tmp/aoc16/%d", nanos)
 	err := os.MkdirAll(dirp, 0755)
 	shared.Die(err, "Failed to create draw dir.")
-	brd := shared.NewBoard(append([]string{}, lines...))
+	var seats []shared.Loc
 	for ; step != nil; step = step.Parent {
-		brd.Set(step.Item, 'O')
+		seats = append(seats, step.Item)
 	}
-	content := strings.Join(brd.GetLines(), "\n") + "\n"
+	scene := render.NewScene(shared.NewBoard(lines), render.Mark(seats, 'O', render.Yellow))
+	content := strings.Join(render.Lines(scene), "\n") + "\n"
 	filep := filepath.Join(dirp, "board.txt")
 	err = os.WriteFile(filep, []byte(content), 0644)
 	shared.Die(err, "Failed to write board.txt.")
-	shared.Logger.Info("Winner drawn.", "filepath", filep)
+	f, err := os.Create(filepath.Join(dirp, "board.png"))
+	shared.Die(err, "Failed to create board.png.")
+	defer f.Close()
+	shared.Die(render.PNG(f, scene, 4), "Failed to write board.png.")
+	shared.Logger.Info("Winner drawn.", "directory", dirp)
 }
 
 func sortDirections(start, end shared.Loc) []shared.Direction {
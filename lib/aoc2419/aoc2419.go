@@ -0,0 +1,44 @@
+package aoc2419
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/denarced/advent-of-code/shared"
+)
+
+// CountDesigns counts the designs that can be built from the towel patterns and the total number
+// of ways they can be built.
+func CountDesigns(lines []string) (possible int, ways *big.Int) {
+	shared.Logger.Info("Count designs.", "line count", len(lines))
+	ways = new(big.Int)
+	if len(lines) == 0 {
+		return
+	}
+	trie := shared.NewTrie(parsePatterns(lines[0])...)
+	for _, each := range lines[1:] {
+		design := strings.TrimSpace(each)
+		if design == "" {
+			continue
+		}
+		count := trie.CountSegmentations(design)
+		shared.Logger.Debug("Design counted.", "design", design, "count", count)
+		if count.Sign() > 0 {
+			possible++
+			ways.Add(ways, count)
+		}
+	}
+	shared.Logger.Info("Designs counted.", "possible", possible, "ways", ways)
+	return
+}
+
+func parsePatterns(line string) []string {
+	var patterns []string
+	for _, each := range strings.Split(line, ",") {
+		trimmed := strings.TrimSpace(each)
+		if trimmed != "" {
+			patterns = append(patterns, trimmed)
+		}
+	}
+	return patterns
+}
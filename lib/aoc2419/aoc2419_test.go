@@ -0,0 +1,50 @@
+package aoc2419
+
+import (
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountDesigns(t *testing.T) {
+	run := func(name string, lines []string, expectedPossible int, expectedWays int64) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+
+			// EXERCISE
+			possible, ways := CountDesigns(lines)
+
+			// VERIFY
+			req.Equal(expectedPossible, possible)
+			req.Equal(expectedWays, ways.Int64())
+		})
+	}
+
+	run("empty", []string{}, 0, 0)
+	run("no designs", []string{"r, wr"}, 0, 0)
+	run(
+		"example",
+		[]string{
+			"r, wr, b, g, bwu, rb, gb, br",
+			"",
+			"brwrr",
+			"bggr",
+			"gbbr",
+			"rrbgbr",
+			"ubwu",
+			"bwurrg",
+			"brgr",
+			"bbrgwb",
+		},
+		6,
+		16,
+	)
+}
+
+func TestParsePatterns(t *testing.T) {
+	req := require.New(t)
+	req.Equal([]string{"r", "wr", "bwu"}, parsePatterns("r, wr,  bwu"))
+	req.Nil(parsePatterns(""))
+}
@@ -0,0 +1,67 @@
+package aoc2420
+
+import (
+	"github.com/denarced/advent-of-code/shared"
+)
+
+// CountCheats counts the cheats that save at least minSaving picoseconds when the wall can be
+// passed through for at most cheatLength picoseconds.
+func CountCheats(lines []string, cheatLength, minSaving int) int {
+	shared.Logger.Info(
+		"Count cheats.",
+		"cheat length",
+		cheatLength,
+		"minimum saving",
+		minSaving,
+	)
+	if len(lines) == 0 {
+		return 0
+	}
+	brd := shared.NewBoard(lines)
+	brd.ReadOnly = true
+	start := brd.FindOrDie('S')
+	end := brd.FindOrDie('E')
+	fromStart := measureDistances(brd, start)
+	fromEnd := measureDistances(brd, end)
+	total, ok := fromStart[end]
+	if !ok {
+		panic("No track from start to end.")
+	}
+	shared.Logger.Info("Track measured.", "length", total, "cell count", len(fromStart))
+
+	count := 0
+	for loc, distance := range fromStart {
+		for dx := -cheatLength; dx <= cheatLength; dx++ {
+			remaining := cheatLength - shared.Abs(dx)
+			for dy := -remaining; dy <= remaining; dy++ {
+				rest, ok := fromEnd[loc.Delta(shared.Loc{X: dx, Y: dy})]
+				if !ok {
+					continue
+				}
+				cheated := distance + shared.Abs(dx) + shared.Abs(dy) + rest
+				if total-cheated >= minSaving {
+					count++
+				}
+			}
+		}
+	}
+	shared.Logger.Info("Cheats counted.", "count", count)
+	return count
+}
+
+func measureDistances(brd *shared.Board, from shared.Loc) map[shared.Loc]int {
+	distances := map[shared.Loc]int{from: 0}
+	queue := []shared.Loc{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, each := range brd.NextTo(current, []rune{'.', 'S', 'E'}, false) {
+			if _, ok := distances[each]; ok {
+				continue
+			}
+			distances[each] = distances[current] + 1
+			queue = append(queue, each)
+		}
+	}
+	return distances
+}
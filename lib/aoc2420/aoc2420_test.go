@@ -0,0 +1,80 @@
+package aoc2420
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/stretchr/testify/require"
+)
+
+var example = []string{
+	"###############",
+	"#...#...#.....#",
+	"#.#.#.#.#.###.#",
+	"#S#...#.#.#...#",
+	"#######.#.#.###",
+	"#######.#.#...#",
+	"#######.#.###.#",
+	"###..E#...#...#",
+	"###.#######.###",
+	"#...###...#...#",
+	"#.#####.#.###.#",
+	"#.#...#.#.#...#",
+	"#.#.#.#.#.#.###",
+	"#...#...#...###",
+	"###############",
+}
+
+func TestCountCheats(t *testing.T) {
+	run := func(lines []string, cheatLength, minSaving, expected int) {
+		name := fmt.Sprintf("%d-%d", cheatLength, minSaving)
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+
+			// EXERCISE
+			actual := CountCheats(lines, cheatLength, minSaving)
+
+			// VERIFY
+			req.Equal(expected, actual)
+		})
+	}
+
+	run([]string{}, 2, 1, 0)
+
+	run(example, 2, 65, 0)
+	run(example, 2, 64, 1)
+	run(example, 2, 40, 2)
+	run(example, 2, 38, 3)
+	run(example, 2, 36, 4)
+	run(example, 2, 20, 5)
+	run(example, 2, 12, 8)
+	run(example, 2, 10, 10)
+	run(example, 2, 8, 14)
+	run(example, 2, 6, 16)
+	run(example, 2, 4, 30)
+	run(example, 2, 2, 44)
+
+	run(example, 20, 77, 0)
+	run(example, 20, 76, 3)
+	run(example, 20, 74, 7)
+	run(example, 20, 72, 29)
+	run(example, 20, 50, 285)
+}
+
+func TestMeasureDistances(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	brd := shared.NewBoard([]string{
+		"#####",
+		"#S..#",
+		"###E#",
+		"#####",
+	})
+	distances := measureDistances(brd, brd.FindOrDie('S'))
+	req.Equal(
+		map[shared.Loc]int{{X: 1, Y: 2}: 0, {X: 2, Y: 2}: 1, {X: 3, Y: 2}: 2, {X: 3, Y: 1}: 3},
+		distances,
+	)
+}
@@ -0,0 +1,239 @@
+package aoc2421
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/denarced/advent-of-code/shared"
+)
+
+const activate = 'A'
+
+var (
+	// NumericLayout is the layout of the door keypad.
+	NumericLayout = []string{
+		"789",
+		"456",
+		"123",
+		" 0A",
+	}
+	// DirectionalLayout is the layout of the robot keypads.
+	DirectionalLayout = []string{
+		" ^A",
+		"<v>",
+	}
+)
+
+// Keypad is a keypad read from a layout where each rune is a key and space is a gap.
+type Keypad struct {
+	keys map[rune]shared.Loc
+	// locs has the key at each location. Gaps and locations outside of the layout aren't in it.
+	locs map[shared.Loc]rune
+}
+
+func NewKeypad(layout []string) *Keypad {
+	pad := &Keypad{
+		keys: map[rune]shared.Loc{},
+		locs: map[shared.Loc]rune{},
+	}
+	shared.NewBoard(layout).Iter(func(loc shared.Loc, c rune) bool {
+		if c == ' ' {
+			return true
+		}
+		if _, ok := pad.keys[c]; ok {
+			panic(fmt.Sprintf("Duplicate key in layout: %s.", string(c)))
+		}
+		pad.keys[c] = loc
+		pad.locs[loc] = c
+		return true
+	})
+	return pad
+}
+
+func (v *Keypad) locate(key rune) shared.Loc {
+	loc, ok := v.keys[key]
+	if !ok {
+		panic(fmt.Sprintf("No such key: %s.", string(key)))
+	}
+	return loc
+}
+
+var buttonDeltas = map[rune]shared.Loc{
+	'^': {X: 0, Y: 1},
+	'v': {X: 0, Y: -1},
+	'<': {X: -1, Y: 0},
+	'>': {X: 1, Y: 0},
+}
+
+// paths returns the button sequences, each ending with activation, that move from one key to
+// another without passing over a gap. The two L-shaped paths are preferred because alternating
+// between directions is never cheaper. When a gap blocks both of them, all the shortest paths
+// around the gaps are returned instead.
+func (v *Keypad) paths(from, to rune) []string {
+	start := v.locate(from)
+	end := v.locate(to)
+	dx := end.X - start.X
+	dy := end.Y - start.Y
+	horizontal := strings.Repeat(string(shared.Or(dx < 0, '<', '>')), shared.Abs(dx))
+	vertical := strings.Repeat(string(shared.Or(dy < 0, 'v', '^')), shared.Abs(dy))
+	var paths []string
+	for _, each := range []string{horizontal + vertical, vertical + horizontal} {
+		path := each + string(activate)
+		if v.follows(start, each) && !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		paths = v.searchPaths(start, end)
+	}
+	if len(paths) == 0 {
+		panic(fmt.Sprintf("No path from %s to %s.", string(from), string(to)))
+	}
+	return paths
+}
+
+// follows checks that the buttons move from start over keys only.
+func (v *Keypad) follows(start shared.Loc, buttons string) bool {
+	loc := start
+	for _, each := range buttons {
+		loc = loc.Delta(buttonDeltas[each])
+		if _, ok := v.locs[loc]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// searchPaths finds all the shortest paths from start to end with a breadth-first search over the
+// keys.
+func (v *Keypad) searchPaths(start, end shared.Loc) []string {
+	// Distances to end so that the paths can be followed from start.
+	distances := map[shared.Loc]int{end: 0}
+	queue := []shared.Loc{end}
+	for len(queue) > 0 {
+		loc := queue[0]
+		queue = queue[1:]
+		for _, delta := range buttonDeltas {
+			next := loc.Delta(delta)
+			if _, ok := v.locs[next]; !ok {
+				continue
+			}
+			if _, ok := distances[next]; !ok {
+				distances[next] = distances[loc] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	if _, ok := distances[start]; !ok {
+		return nil
+	}
+	var paths []string
+	var follow func(loc shared.Loc, buttons string)
+	follow = func(loc shared.Loc, buttons string) {
+		if loc == end {
+			paths = append(paths, buttons+string(activate))
+			return
+		}
+		for button, delta := range buttonDeltas {
+			next := loc.Delta(delta)
+			if distance, ok := distances[next]; ok && distance == distances[loc]-1 {
+				follow(next, buttons+string(button))
+			}
+		}
+	}
+	follow(start, "")
+	slices.Sort(paths)
+	return paths
+}
+
+type move struct {
+	layer    int
+	from, to rune
+}
+
+// chain is a chain of keypads where the first one is typed by the last robot and the rest are
+// typed by the robot or person above them.
+type chain struct {
+	pads  []*Keypad
+	costs map[move]int
+}
+
+func newChain(pads ...*Keypad) *chain {
+	return &chain{
+		pads:  pads,
+		costs: map[move]int{},
+	}
+}
+
+// sequenceCost derives the human button presses required to type sequence on the keypad at layer.
+func (v *chain) sequenceCost(layer int, sequence string) int {
+	if layer == len(v.pads) {
+		return len(sequence)
+	}
+	total := 0
+	previous := rune(activate)
+	for _, each := range sequence {
+		total += v.moveCost(layer, previous, each)
+		previous = each
+	}
+	return total
+}
+
+func (v *chain) moveCost(layer int, from, to rune) int {
+	key := move{layer: layer, from: from, to: to}
+	if cost, ok := v.costs[key]; ok {
+		return cost
+	}
+	cost := -1
+	for _, each := range v.pads[layer].paths(from, to) {
+		candidate := v.sequenceCost(layer+1, each)
+		if cost < 0 || candidate < cost {
+			cost = candidate
+		}
+	}
+	v.costs[key] = cost
+	return cost
+}
+
+// DeriveSequenceLength derives the length of the shortest human button sequence that types code
+// on the first keypad through the rest of the keypads.
+func DeriveSequenceLength(code string, pads ...*Keypad) int {
+	return newChain(pads...).sequenceCost(0, code)
+}
+
+// SumComplexities sums the complexities of the codes when robotCount directional keypads are
+// between the person and the numeric keypad.
+func SumComplexities(lines []string, robotCount int) int {
+	shared.Logger.Info("Sum complexities.", "robot count", robotCount)
+	pads := []*Keypad{NewKeypad(NumericLayout)}
+	directional := NewKeypad(DirectionalLayout)
+	for range robotCount {
+		pads = append(pads, directional)
+	}
+	aChain := newChain(pads...)
+	sum := 0
+	for _, each := range lines {
+		code := strings.TrimSpace(each)
+		if code == "" {
+			continue
+		}
+		length := aChain.sequenceCost(0, code)
+		numeric := parseNumeric(code)
+		shared.Logger.Debug("Code derived.", "code", code, "length", length, "numeric", numeric)
+		sum += length * numeric
+	}
+	shared.Logger.Info("Complexities summed.", "sum", sum)
+	return sum
+}
+
+func parseNumeric(code string) int {
+	digits := strings.TrimLeft(strings.TrimRight(code, string(activate)), "0")
+	if digits == "" {
+		return 0
+	}
+	i, err := strconv.Atoi(digits)
+	shared.Die(err, "parseNumeric")
+	return i
+}
@@ -0,0 +1,116 @@
+package aoc2421
+
+import (
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/stretchr/testify/require"
+)
+
+var example = []string{
+	"029A",
+	"980A",
+	"179A",
+	"456A",
+	"379A",
+}
+
+func TestSumComplexities(t *testing.T) {
+	run := func(name string, lines []string, robotCount, expected int) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+
+			// EXERCISE
+			actual := SumComplexities(lines, robotCount)
+
+			// VERIFY
+			req.Equal(expected, actual)
+		})
+	}
+
+	run("empty", []string{}, 2, 0)
+	run("example with 2", example, 2, 126384)
+	run("example with 25", example, 25, 154115708116294)
+}
+
+func TestDeriveSequenceLength(t *testing.T) {
+	numeric := NewKeypad(NumericLayout)
+	directional := NewKeypad(DirectionalLayout)
+	run := func(code string, robotCount, expected int) {
+		t.Run(code, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			pads := []*Keypad{numeric}
+			for range robotCount {
+				pads = append(pads, directional)
+			}
+
+			// EXERCISE & VERIFY
+			require.Equal(t, expected, DeriveSequenceLength(code, pads...))
+		})
+	}
+
+	// <A^A>^^AvvvA
+	run("029A", 0, 12)
+	// v<<A>>^A<A>AvA<^AA>A<vAAA>^A
+	run("029A", 1, 28)
+	run("029A", 2, 68)
+	run("980A", 2, 60)
+	run("179A", 2, 68)
+	run("456A", 2, 64)
+	run("379A", 2, 64)
+}
+
+func TestPaths(t *testing.T) {
+	run := func(layout []string, from, to rune, expected []string) {
+		t.Run(string([]rune{from, to}), func(t *testing.T) {
+			shared.InitTestLogging(t)
+			pad := NewKeypad(layout)
+
+			// EXERCISE & VERIFY
+			require.ElementsMatch(t, expected, pad.paths(from, to))
+		})
+	}
+
+	run(NumericLayout, 'A', 'A', []string{"A"})
+	run(NumericLayout, 'A', '0', []string{"<A"})
+	run(NumericLayout, 'A', '9', []string{"^^^A"})
+	run(NumericLayout, '2', '9', []string{">^^A", "^^>A"})
+	// Going left first would hit the gap.
+	run(NumericLayout, 'A', '1', []string{"^<<A"})
+	run(NumericLayout, '1', '0', []string{">vA"})
+	run(DirectionalLayout, 'A', '<', []string{"v<<A"})
+	run(DirectionalLayout, '<', '^', []string{">^A"})
+	run([]string{"ab", " c"}, 'c', 'a', []string{"^<A"})
+	// Both corners are gaps so the path has to go around them.
+	run([]string{"A d", "bc ", " ef"}, 'A', 'f', []string{"v>v>A"})
+	run([]string{"A b", "cde"}, 'A', 'b', []string{"v>>^A"})
+	run([]string{"A  ", "bcd", "e f"}, 'A', 'f', []string{"v>>vA"})
+}
+
+func TestPathsWithoutRoute(t *testing.T) {
+	shared.InitTestLogging(t)
+	pad := NewKeypad([]string{"A ", " b"})
+
+	// EXERCISE & VERIFY
+	require.PanicsWithValue(t, "No path from A to b.", func() { pad.paths('A', 'b') })
+}
+
+func TestDeriveSequenceLengthAroundGaps(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	pad := NewKeypad([]string{"A d", "bc ", " ef"})
+
+	// EXERCISE & VERIFY
+	// v>v>A
+	req.Equal(5, DeriveSequenceLength("f", pad))
+	// <vA>A<A>A^A
+	req.Equal(11, DeriveSequenceLength("f", pad, NewKeypad(DirectionalLayout)))
+}
+
+func TestParseNumeric(t *testing.T) {
+	req := require.New(t)
+	req.Equal(29, parseNumeric("029A"))
+	req.Equal(980, parseNumeric("980A"))
+	req.Equal(0, parseNumeric("000A"))
+}
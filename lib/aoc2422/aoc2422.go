@@ -0,0 +1,132 @@
+package aoc2422
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/denarced/advent-of-code/shared"
+)
+
+const (
+	pruneModulo = 16_777_216
+	// Price changes are -9..9 so they fit in base 19 after adding 9.
+	changeBase     = 19
+	changeOffset   = 9
+	sequenceLength = 4
+	sequenceCount  = changeBase * changeBase * changeBase * changeBase
+)
+
+func evolve(secret int) int {
+	secret = ((secret * 64) ^ secret) % pruneModulo
+	secret = ((secret / 32) ^ secret) % pruneModulo
+	return ((secret * 2048) ^ secret) % pruneModulo
+}
+
+// SumSecrets sums each buyer's secret after evolving it steps times.
+func SumSecrets(lines []string, steps int) int {
+	secrets := parseSecrets(lines)
+	shared.Logger.Info("Sum secrets.", "buyer count", len(secrets), "steps", steps)
+	sum := 0
+	for _, each := range secrets {
+		for range steps {
+			each = evolve(each)
+		}
+		sum += each
+	}
+	shared.Logger.Info("Secrets summed.", "sum", sum)
+	return sum
+}
+
+// FindBestSequence finds the sequence of four price changes that yields the most bananas across
+// all buyers.
+func FindBestSequence(lines []string, steps int) (bananas int, changes []int) {
+	secrets := parseSecrets(lines)
+	workerCount := shared.Max(1, shared.Min(runtime.NumCPU(), len(secrets)))
+	shared.Logger.Info(
+		"Find best sequence.",
+		"buyer count",
+		len(secrets),
+		"steps",
+		steps,
+		"worker count",
+		workerCount,
+	)
+	if len(secrets) == 0 {
+		return 0, nil
+	}
+
+	partials := make([][]int, workerCount)
+	var wg sync.WaitGroup
+	for worker := range workerCount {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			totals := make([]int, sequenceCount)
+			seen := make([]int, sequenceCount)
+			for i := worker; i < len(secrets); i += workerCount {
+				// Buyer index + 1 so that zero means never seen.
+				addPrices(secrets[i], steps, i+1, totals, seen)
+			}
+			partials[worker] = totals
+		}()
+	}
+	wg.Wait()
+
+	// Merge in worker order so that the result doesn't depend on scheduling.
+	totals := partials[0]
+	for _, each := range partials[1:] {
+		for i, count := range each {
+			totals[i] += count
+		}
+	}
+	best := 0
+	for i, count := range totals {
+		if count > totals[best] {
+			best = i
+		}
+	}
+	bananas = totals[best]
+	changes = unpackChanges(best)
+	shared.Logger.Info("Best sequence found.", "bananas", bananas, "changes", changes)
+	return
+}
+
+// addPrices adds the price of each change sequence's first occurrence to totals. Seen is shared
+// between buyers and marked with the buyer's stamp to avoid clearing it for each buyer.
+func addPrices(secret, steps, stamp int, totals, seen []int) {
+	packed := 0
+	previous := secret % 10
+	for i := range steps {
+		secret = evolve(secret)
+		price := secret % 10
+		packed = (packed*changeBase + price - previous + changeOffset) % sequenceCount
+		previous = price
+		if i < sequenceLength-1 || seen[packed] == stamp {
+			continue
+		}
+		seen[packed] = stamp
+		totals[packed] += price
+	}
+}
+
+func unpackChanges(packed int) []int {
+	changes := make([]int, sequenceLength)
+	for i := sequenceLength - 1; i >= 0; i-- {
+		changes[i] = packed%changeBase - changeOffset
+		packed /= changeBase
+	}
+	return changes
+}
+
+func parseSecrets(lines []string) []int {
+	var secrets []int
+	for _, each := range lines {
+		trimmed := strings.TrimSpace(each)
+		if trimmed == "" {
+			continue
+		}
+		secrets = append(secrets, shared.ParseIntOrDie(trimmed))
+	}
+	return secrets
+}
@@ -0,0 +1,75 @@
+package aoc2422
+
+import (
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvolve(t *testing.T) {
+	req := require.New(t)
+	secret := 123
+	var actual []int
+	for range 10 {
+		secret = evolve(secret)
+		actual = append(actual, secret)
+	}
+	req.Equal(
+		[]int{
+			15887950,
+			16495136,
+			527345,
+			704524,
+			1553684,
+			12683156,
+			11100544,
+			12249484,
+			7753432,
+			5908254,
+		},
+		actual,
+	)
+}
+
+func TestSumSecrets(t *testing.T) {
+	run := func(name string, lines []string, expected int) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+
+			// EXERCISE & VERIFY
+			require.Equal(t, expected, SumSecrets(lines, 2000))
+		})
+	}
+
+	run("empty", []string{}, 0)
+	run("example", []string{"1", "10", "100", "2024"}, 37327623)
+}
+
+func TestFindBestSequence(t *testing.T) {
+	run := func(name string, lines []string, steps, expectedBananas int, expectedChanges []int) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+
+			// EXERCISE
+			bananas, changes := FindBestSequence(lines, steps)
+
+			// VERIFY
+			req.Equal(expectedBananas, bananas)
+			req.Equal(expectedChanges, changes)
+		})
+	}
+
+	run("empty", []string{}, 2000, 0, nil)
+	// Prices of 123: 3, 0, 6, 5, 4, 4, 6, 4, 4, 2.
+	run("123", []string{"123"}, 9, 6, []int{-1, -1, 0, 2})
+	run("example", []string{"1", "2", "3", "2024"}, 2000, 23, []int{-2, 1, -1, 3})
+}
+
+func TestUnpackChanges(t *testing.T) {
+	req := require.New(t)
+	req.Equal([]int{-9, -9, -9, -9}, unpackChanges(0))
+	req.Equal([]int{9, 9, 9, 9}, unpackChanges(sequenceCount-1))
+	req.Equal([]int{-9, -9, -9, -8}, unpackChanges(1))
+}
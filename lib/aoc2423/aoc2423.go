@@ -0,0 +1,35 @@
+package aoc2423
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/graph"
+)
+
+// CountTriangles counts the sets of three interconnected computers where at least one computer's
+// name starts with prefix.
+func CountTriangles(lines []string, prefix string) int {
+	g := graph.NewUndirected(graph.ParseEdges(lines))
+	shared.Logger.Info("Count triangles.", "node count", len(g.Names()), "prefix", prefix)
+	count := 0
+	for _, each := range g.Triangles() {
+		if slices.ContainsFunc(each, func(name string) bool {
+			return strings.HasPrefix(name, prefix)
+		}) {
+			count++
+		}
+	}
+	shared.Logger.Info("Triangles counted.", "count", count)
+	return count
+}
+
+// DerivePassword derives the LAN party password: the names of the computers in the largest set of
+// interconnected computers, sorted and joined with commas.
+func DerivePassword(lines []string) string {
+	g := graph.NewUndirected(graph.ParseEdges(lines))
+	clique := g.MaximumClique()
+	shared.Logger.Info("Largest clique found.", "size", len(clique))
+	return strings.Join(clique, ",")
+}
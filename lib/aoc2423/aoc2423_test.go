@@ -0,0 +1,44 @@
+package aoc2423
+
+import (
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/stretchr/testify/require"
+)
+
+var example = []string{
+	"kh-tc", "qp-kh", "de-cg", "ka-co", "yn-aq", "qp-ub", "cg-tb", "vc-aq",
+	"tb-ka", "wh-tc", "yn-cg", "kh-ub", "ta-co", "de-co", "tc-td", "tb-wq",
+	"wh-td", "ta-ka", "td-qp", "aq-cg", "wq-ub", "ub-vc", "de-ta", "wq-aq",
+	"wq-vc", "wh-yn", "ka-de", "kh-ta", "co-tc", "wh-qp", "tb-vc", "td-yn",
+}
+
+func TestCountTriangles(t *testing.T) {
+	run := func(name string, lines []string, prefix string, expected int) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+
+			// EXERCISE & VERIFY
+			require.Equal(t, expected, CountTriangles(lines, prefix))
+		})
+	}
+
+	run("empty", []string{}, "t", 0)
+	run("example, all", example, "", 12)
+	run("example, t", example, "t", 7)
+}
+
+func TestDerivePassword(t *testing.T) {
+	run := func(name string, lines []string, expected string) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+
+			// EXERCISE & VERIFY
+			require.Equal(t, expected, DerivePassword(lines))
+		})
+	}
+
+	run("empty", []string{}, "")
+	run("example", example, "co,de,ka,ta")
+}
@@ -0,0 +1,375 @@
+package aoc2424
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/gent"
+)
+
+const (
+	opAnd = "AND"
+	opOr  = "OR"
+	opXor = "XOR"
+)
+
+var operators = map[string]func(a, b bool) bool{
+	opAnd: func(a, b bool) bool { return a && b },
+	opOr:  func(a, b bool) bool { return a || b },
+	opXor: func(a, b bool) bool { return a != b },
+}
+
+// Gate is a named component whose output is evaluated lazily from its inputs. Initial wires are
+// gates without inputs.
+type Gate struct {
+	Output string
+	Op     string
+	Inputs []string
+}
+
+// Circuit is a network of gates keyed by their output wire.
+type Circuit struct {
+	gates   map[string]*Gate
+	values  map[string]bool
+	pending map[string]bool
+	// Wires that each wire is an input for.
+	consumers map[string][]*Gate
+}
+
+func NewCircuit(lines []string) *Circuit {
+	shared.Logger.Info("Create circuit.", "line count", len(lines))
+	circuit := &Circuit{
+		gates:     map[string]*Gate{},
+		values:    map[string]bool{},
+		pending:   map[string]bool{},
+		consumers: map[string][]*Gate{},
+	}
+	for _, each := range lines {
+		trimmed := strings.TrimSpace(each)
+		if trimmed == "" {
+			continue
+		}
+		if strings.Contains(trimmed, ":") {
+			pieces := strings.Split(trimmed, ":")
+			name := strings.TrimSpace(pieces[0])
+			circuit.add(&Gate{Output: name})
+			circuit.values[name] = strings.TrimSpace(pieces[1]) == "1"
+			continue
+		}
+		// E.g. "x00 AND y00 -> z00".
+		fields := strings.Fields(trimmed)
+		if len(fields) != 5 || fields[3] != "->" || operators[fields[1]] == nil {
+			panic(fmt.Sprintf("Invalid gate: %s.", trimmed))
+		}
+		circuit.add(&Gate{Output: fields[4], Op: fields[1], Inputs: []string{fields[0], fields[2]}})
+	}
+	shared.Logger.Info("Circuit created.", "gate count", len(circuit.gates))
+	return circuit
+}
+
+func (v *Circuit) add(g *Gate) {
+	if _, ok := v.gates[g.Output]; ok {
+		panic(fmt.Sprintf("Wire has two sources: %s.", g.Output))
+	}
+	v.gates[g.Output] = g
+	for _, each := range g.Inputs {
+		v.consumers[each] = append(v.consumers[each], g)
+	}
+}
+
+// Evaluate evaluates wire's value, evaluating its inputs first when necessary.
+func (v *Circuit) Evaluate(wire string) bool {
+	value, err := v.evaluate(wire)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to evaluate %s: %s.", wire, err))
+	}
+	return value
+}
+
+func (v *Circuit) evaluate(wire string) (bool, error) {
+	if value, ok := v.values[wire]; ok {
+		return value, nil
+	}
+	g := v.gates[wire]
+	if g == nil || g.Op == "" {
+		return false, fmt.Errorf("no value for wire %s", wire)
+	}
+	if v.pending[wire] {
+		return false, fmt.Errorf("wire %s depends on itself", wire)
+	}
+	v.pending[wire] = true
+	defer delete(v.pending, wire)
+	a, err := v.evaluate(g.Inputs[0])
+	if err != nil {
+		return false, err
+	}
+	b, err := v.evaluate(g.Inputs[1])
+	if err != nil {
+		return false, err
+	}
+	value := operators[g.Op](a, b)
+	v.values[wire] = value
+	return value, nil
+}
+
+// Number evaluates the wires starting with prefix and reads them as a binary number where the
+// wire numbered 00 is the least significant bit.
+func (v *Circuit) Number(prefix string) int {
+	number, err := v.number(prefix)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to evaluate %s wires: %s.", prefix, err))
+	}
+	return number
+}
+
+func (v *Circuit) number(prefix string) (int, error) {
+	number := 0
+	for _, each := range v.wiresWithPrefix(prefix) {
+		on, err := v.evaluate(each)
+		if err != nil {
+			return 0, err
+		}
+		if on {
+			number |= 1 << parseBit(each)
+		}
+	}
+	return number, nil
+}
+
+func (v *Circuit) wiresWithPrefix(prefix string) []string {
+	var wires []string
+	for each := range v.gates {
+		if strings.HasPrefix(each, prefix) {
+			wires = append(wires, each)
+		}
+	}
+	slices.Sort(wires)
+	return wires
+}
+
+func parseBit(wire string) int {
+	bit, err := strconv.Atoi(wire[1:])
+	if err != nil {
+		panic(fmt.Sprintf("Invalid bit wire: %s.", wire))
+	}
+	return bit
+}
+
+// DeriveZNumber derives the number that the z wires output.
+func DeriveZNumber(lines []string) int {
+	number := NewCircuit(lines).Number("z")
+	shared.Logger.Info("Z number derived.", "number", number)
+	return number
+}
+
+// Fault is a gate whose output wire doesn't fit in a ripple-carry adder.
+type Fault struct {
+	Wire string
+	// Bit is the position of the full adder that the gate belongs to.
+	Bit    int
+	Reason string
+}
+
+// FindFaults checks each gate against the structure of a ripple-carry adder:
+//
+//	x XOR y -> s    (x00 XOR y00 -> z00 for the first bit)
+//	x AND y -> a    (x00 AND y00 -> carry for the first bit)
+//	s XOR carry -> z
+//	s AND carry -> b
+//	a OR b -> carry (the last carry is the last z)
+//
+// The checks are heuristic: a swap can go unnoticed and a fault doesn't tell what it's swapped
+// with. See FindSwaps. Faults are sorted by wire.
+func FindFaults(lines []string) []Fault {
+	faults := NewCircuit(lines).findFaults()
+	shared.Logger.Info("Faults found.", "count", len(faults))
+	return faults
+}
+
+func (v *Circuit) findFaults() []Fault {
+	zWires := v.wiresWithPrefix("z")
+	if len(zWires) == 0 {
+		return nil
+	}
+	lastZ := zWires[len(zWires)-1]
+	var faults []Fault
+	for _, g := range v.gates {
+		if g.Op == "" {
+			continue
+		}
+		if reason := v.check(g, lastZ); reason != "" {
+			faults = append(faults, Fault{Wire: g.Output, Bit: v.findBit(g), Reason: reason})
+		}
+	}
+	slices.SortFunc(faults, func(a, b Fault) int {
+		return strings.Compare(a.Wire, b.Wire)
+	})
+	return faults
+}
+
+func (v *Circuit) check(g *Gate, lastZ string) string {
+	fromInputs := isInput(g.Inputs[0]) && isInput(g.Inputs[1])
+	firstBit := slices.Contains(g.Inputs, "x00")
+	if g.Output == lastZ {
+		if g.Op != opOr {
+			return "last z isn't a carry OR"
+		}
+		return ""
+	}
+	if strings.HasPrefix(g.Output, "z") {
+		if g.Op != opXor {
+			return "z isn't a sum XOR"
+		}
+		if fromInputs && !firstBit {
+			return "z skips the carry"
+		}
+		return ""
+	}
+	switch g.Op {
+	case opXor:
+		if !fromInputs {
+			return "sum XOR doesn't output z"
+		}
+		if !v.feeds(g.Output, opXor) {
+			return "input XOR doesn't feed a sum XOR"
+		}
+	case opAnd:
+		if firstBit {
+			// The first carry feeds the next bit as a carry does.
+			if !v.feeds(g.Output, opXor) {
+				return "first carry doesn't feed a sum XOR"
+			}
+			return ""
+		}
+		if !v.feeds(g.Output, opOr) {
+			return "AND doesn't feed a carry OR"
+		}
+	case opOr:
+		if !v.feeds(g.Output, opXor) {
+			return "carry OR doesn't feed a sum XOR"
+		}
+	}
+	return ""
+}
+
+func (v *Circuit) feeds(wire, op string) bool {
+	return slices.ContainsFunc(v.consumers[wire], func(g *Gate) bool {
+		return g.Op == op
+	})
+}
+
+func isInput(wire string) bool {
+	return strings.HasPrefix(wire, "x") || strings.HasPrefix(wire, "y")
+}
+
+// findBit finds the highest input bit that g depends on. It's the position of the full adder the
+// gate belongs to.
+func (v *Circuit) findBit(g *Gate) int {
+	if strings.HasPrefix(g.Output, "z") {
+		return parseBit(g.Output)
+	}
+	bit := -1
+	visited := map[string]bool{}
+	queue := slices.Clone(g.Inputs)
+	for len(queue) > 0 {
+		wire := queue[0]
+		queue = queue[1:]
+		if visited[wire] {
+			continue
+		}
+		visited[wire] = true
+		if isInput(wire) {
+			bit = shared.Max(bit, parseBit(wire))
+			continue
+		}
+		if source := v.gates[wire]; source != nil {
+			queue = append(queue, source.Inputs...)
+		}
+	}
+	return bit
+}
+
+// FindSwaps finds the pairs of gates whose outputs are swapped. The faulty wires are paired up so
+// that swapping them back passes the structural checks and the circuit really adds. It's an error
+// if there aren't pairCount pairs of faulty wires or if they can't be paired up into an adder.
+func FindSwaps(lines []string, pairCount int) ([]shared.Pair[string], error) {
+	circuit := NewCircuit(lines)
+	faults := circuit.findFaults()
+	if len(faults) != 2*pairCount {
+		return nil, fmt.Errorf("found %d faulty wires, expected %d", len(faults), 2*pairCount)
+	}
+	wires := gent.Map(faults, func(f Fault) string { return f.Wire })
+	pairs, ok := circuit.pairUp(wires, nil)
+	if !ok {
+		return nil, fmt.Errorf("faulty wires %v can't be paired up into a ripple-carry adder", wires)
+	}
+	shared.Logger.Info("Swaps found.", "pairs", pairs)
+	return pairs, nil
+}
+
+// pairUp swaps the first wire with each of the others in turn and pairs up the rest recursively.
+// The circuit is left as it was.
+func (v *Circuit) pairUp(
+	wires []string,
+	pairs []shared.Pair[string],
+) ([]shared.Pair[string], bool) {
+	if len(wires) == 0 {
+		return pairs, len(v.findFaults()) == 0 && v.adds()
+	}
+	for i := 1; i < len(wires); i++ {
+		v.swap(wires[0], wires[i])
+		rest := slices.Concat(wires[1:i], wires[i+1:])
+		found, ok := v.pairUp(rest, append(slices.Clone(pairs), shared.NewPair(wires[0], wires[i])))
+		v.swap(wires[0], wires[i])
+		if ok {
+			return found, true
+		}
+	}
+	return nil, false
+}
+
+func (v *Circuit) swap(a, b string) {
+	v.gates[a], v.gates[b] = v.gates[b], v.gates[a]
+	v.gates[a].Output, v.gates[b].Output = a, b
+}
+
+// adds checks that the circuit adds x and y to z for single bits, carries through every bit and
+// a few other numbers. The values of the wires are restored afterwards.
+func (v *Circuit) adds() bool {
+	defer func(values map[string]bool) { v.values = values }(v.values)
+	bitCount := len(v.wiresWithPrefix("x"))
+	all := 1<<bitCount - 1
+	cases := [][2]int{{all, 1}, {all, all}, {0x5555_5555_5555 & all, 0x3333_3333_3333 & all}}
+	for i := range bitCount {
+		cases = append(cases, [2]int{1 << i, 0}, [2]int{0, 1 << i}, [2]int{1 << i, 1 << i})
+	}
+	for _, each := range cases {
+		v.values = map[string]bool{}
+		for i := range bitCount {
+			v.values[fmt.Sprintf("x%02d", i)] = each[0]&(1<<i) != 0
+			v.values[fmt.Sprintf("y%02d", i)] = each[1]&(1<<i) != 0
+		}
+		if sum, err := v.number("z"); err != nil || sum != each[0]+each[1] {
+			return false
+		}
+	}
+	return true
+}
+
+// DeriveSwappedWires derives the wires of the four swapped pairs of the puzzle, sorted and joined
+// with commas.
+func DeriveSwappedWires(lines []string) (string, error) {
+	pairs, err := FindSwaps(lines, 4)
+	if err != nil {
+		return "", err
+	}
+	var wires []string
+	for _, each := range pairs {
+		wires = append(wires, each.First, each.Second)
+	}
+	slices.Sort(wires)
+	return strings.Join(wires, ","), nil
+}
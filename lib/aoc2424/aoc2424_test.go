@@ -0,0 +1,231 @@
+package aoc2424
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/gent"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveZNumber(t *testing.T) {
+	run := func(name string, lines []string, expected int) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+
+			// EXERCISE & VERIFY
+			require.Equal(t, expected, DeriveZNumber(lines))
+		})
+	}
+
+	run("empty", []string{}, 0)
+	run(
+		"small example",
+		[]string{
+			"x00: 1",
+			"x01: 1",
+			"x02: 1",
+			"y00: 0",
+			"y01: 1",
+			"y02: 0",
+			"",
+			"x00 AND y00 -> z00",
+			"x01 XOR y01 -> z01",
+			"x02 OR y02 -> z02",
+		},
+		4,
+	)
+	run(
+		"larger example",
+		[]string{
+			"x00: 1", "x01: 0", "x02: 1", "x03: 1", "x04: 0",
+			"y00: 1", "y01: 1", "y02: 1", "y03: 1", "y04: 1",
+			"",
+			"ntg XOR fgs -> mjb", "y02 OR x01 -> tnw", "kwq OR kpj -> z05",
+			"x00 OR x03 -> fst", "tgd XOR rvg -> z01", "vdt OR tnw -> bfw",
+			"bfw AND frj -> z10", "ffh OR nrd -> bqk", "y00 AND y03 -> djm",
+			"y03 OR y00 -> psh", "bqk OR frj -> z08", "tnw OR fst -> frj",
+			"gnj AND tgd -> z11", "bfw XOR mjb -> z00", "x03 OR x00 -> vdt",
+			"gnj AND wpb -> z02", "x04 AND y00 -> kjc", "djm OR pbm -> qhw",
+			"nrd AND vdt -> hwm", "kjc AND fst -> rvg", "y04 OR y02 -> fgs",
+			"y01 AND x02 -> pbm", "ntg OR kjc -> kwq", "psh XOR fgs -> tgd",
+			"qhw XOR tgd -> z09", "pbm OR djm -> kpj", "x03 XOR y03 -> ffh",
+			"x00 XOR y04 -> ntg", "bfw OR bqk -> z06", "nrd XOR fgs -> wpb",
+			"frj XOR qhw -> z04", "bqk OR frj -> z07", "y03 OR x01 -> nrd",
+			"hwm AND bqk -> z03", "tgd XOR rvg -> z12", "tnw OR pbm -> gnj",
+		},
+		2024,
+	)
+}
+
+func TestEvaluateCycle(t *testing.T) {
+	shared.InitTestLogging(t)
+	circuit := NewCircuit([]string{"x00: 1", "x00 AND b -> a", "x00 AND a -> b"})
+	require.Panics(t, func() { circuit.Evaluate("a") })
+}
+
+// generateAdder generates a ripple-carry adder for bitCount bits and swaps the outputs of the
+// gates in swaps.
+func generateAdder(bitCount int, x, y int, swaps map[string]string) []string {
+	var lines []string
+	for _, prefix := range []string{"x", "y"} {
+		value := shared.Or(prefix == "x", x, y)
+		for i := range bitCount {
+			lines = append(lines, fmt.Sprintf("%s%02d: %d", prefix, i, (value>>i)&1))
+		}
+	}
+	gate := func(a, op, b, output string) {
+		if swapped, ok := swaps[output]; ok {
+			output = swapped
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s -> %s", a, op, b, output))
+	}
+	gate("x00", opXor, "y00", "z00")
+	gate("x00", opAnd, "y00", "c00")
+	for i := 1; i < bitCount; i++ {
+		carry := fmt.Sprintf("c%02d", i-1)
+		gate(fmt.Sprintf("x%02d", i), opXor, fmt.Sprintf("y%02d", i), fmt.Sprintf("s%02d", i))
+		gate(fmt.Sprintf("x%02d", i), opAnd, fmt.Sprintf("y%02d", i), fmt.Sprintf("a%02d", i))
+		gate(fmt.Sprintf("s%02d", i), opXor, carry, fmt.Sprintf("z%02d", i))
+		gate(fmt.Sprintf("s%02d", i), opAnd, carry, fmt.Sprintf("b%02d", i))
+		// The last carry is the most significant z.
+		carryOut := shared.Or(i == bitCount-1, fmt.Sprintf("z%02d", bitCount), fmt.Sprintf("c%02d", i))
+		gate(fmt.Sprintf("a%02d", i), opOr, fmt.Sprintf("b%02d", i), carryOut)
+	}
+	return lines
+}
+
+func TestGenerateAdder(t *testing.T) {
+	shared.InitTestLogging(t)
+	lines := generateAdder(16, 12345, 54321, nil)
+	require.Equal(t, 12345+54321, DeriveZNumber(lines))
+	require.Empty(t, FindFaults(lines))
+}
+
+func swapOutputs(swaps []string) map[string]string {
+	swapMap := map[string]string{}
+	for i := 0; i < len(swaps); i += 2 {
+		swapMap[swaps[i]] = swaps[i+1]
+		swapMap[swaps[i+1]] = swaps[i]
+	}
+	return swapMap
+}
+
+func TestFindFaults(t *testing.T) {
+	run := func(name string, swaps []string, expectedBits []int) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			lines := generateAdder(16, 0, 0, swapOutputs(swaps))
+
+			// EXERCISE
+			faults := FindFaults(lines)
+
+			// VERIFY
+			var bits []int
+			for _, each := range faults {
+				bits = append(bits, each.Bit)
+				req.NotEmpty(each.Reason)
+			}
+			req.Equal(expectedBits, bits)
+		})
+	}
+
+	run("none", nil, nil)
+	run("sum with input and", []string{"s05", "a05"}, []int{5, 5})
+	run("z with carry", []string{"z07", "c07"}, []int{7, 7})
+	run("z with carry and", []string{"z10", "b10"}, []int{10, 10})
+	run("z with input and", []string{"z12", "a12"}, []int{12, 12})
+	run(
+		"four pairs",
+		[]string{"s03", "a03", "z06", "c06", "z09", "b09", "z13", "a13"},
+		[]int{3, 13, 9, 6, 3, 6, 9, 13},
+	)
+}
+
+func TestFindSwaps(t *testing.T) {
+	run := func(name string, swaps []string) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			lines := generateAdder(16, 0, 0, swapOutputs(swaps))
+			var expected []shared.Pair[string]
+			for i := 0; i < len(swaps); i += 2 {
+				expected = append(expected, shared.NewPair(swaps[i], swaps[i+1]))
+			}
+
+			// EXERCISE
+			pairs, err := FindSwaps(lines, len(swaps)/2)
+
+			// VERIFY
+			req.NoError(err)
+			normalize := func(p shared.Pair[string]) string {
+				return strings.Join(slices.Sorted(slices.Values([]string{p.First, p.Second})), ",")
+			}
+			req.ElementsMatch(gent.Map(expected, normalize), gent.Map(pairs, normalize))
+		})
+	}
+
+	run("none", nil)
+	run("sum with input and", []string{"s05", "a05"})
+	run("z with carry", []string{"z07", "c07"})
+	run("four pairs", []string{"s03", "a03", "z06", "c06", "z09", "b09", "z13", "a13"})
+}
+
+func TestFindSwapsErrors(t *testing.T) {
+	run := func(name string, lines []string, pairCount int, expected string) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+
+			// EXERCISE
+			_, err := FindSwaps(lines, pairCount)
+
+			// VERIFY
+			require.EqualError(t, err, expected)
+		})
+	}
+
+	run(
+		"too few",
+		generateAdder(16, 0, 0, swapOutputs([]string{"z07", "c07"})),
+		4,
+		"found 2 faulty wires, expected 8")
+	// Two gates have the wrong operator, nothing is swapped.
+	lines := generateAdder(16, 0, 0, nil)
+	for i, each := range lines {
+		switch each {
+		case "x05 AND y05 -> a05":
+			lines[i] = "x05 OR y05 -> a05"
+		case "x08 AND y08 -> a08":
+			lines[i] = "x08 OR y08 -> a08"
+		}
+	}
+	run(
+		"not swaps",
+		lines,
+		1,
+		"faulty wires [a05 a08] can't be paired up into a ripple-carry adder")
+}
+
+func TestDeriveSwappedWires(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	lines := generateAdder(
+		16,
+		0,
+		0,
+		swapOutputs([]string{"s03", "a03", "z06", "c06", "z09", "b09", "z13", "a13"}))
+
+	// EXERCISE
+	wires, err := DeriveSwappedWires(lines)
+
+	// VERIFY
+	req.NoError(err)
+	req.Equal("a03,a13,b09,c06,s03,z06,z09,z13", wires)
+
+	_, err = DeriveSwappedWires(generateAdder(16, 0, 0, nil))
+	req.EqualError(err, "found 0 faulty wires, expected 8")
+}
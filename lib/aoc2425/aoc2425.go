@@ -0,0 +1,113 @@
+package aoc2425
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/denarced/advent-of-code/shared"
+)
+
+type schematic struct {
+	lock    bool
+	heights []int
+}
+
+// CountFittingPairs counts the lock and key pairs whose columns don't overlap.
+func CountFittingPairs(lines []string) int {
+	blocks := shared.SplitToBlocks(lines)
+	shared.Logger.Info("Count fitting pairs.", "block count", len(blocks))
+	if len(blocks) == 0 {
+		return 0
+	}
+	width := len(blocks[0][0])
+	space := len(blocks[0]) - 2
+	idx := newProfileIndex(width, space)
+	var locks []schematic
+	for _, each := range blocks {
+		if len(each) != space+2 || len(each[0]) != width {
+			panic(fmt.Sprintf("Schematics differ in size: %v.", each))
+		}
+		parsed := parseSchematic(each)
+		if parsed.lock {
+			locks = append(locks, parsed)
+		} else {
+			idx.add(parsed.heights)
+		}
+	}
+	idx.accumulate()
+
+	count := 0
+	for _, each := range locks {
+		room := make([]int, width)
+		for i, height := range each.heights {
+			room[i] = space - height
+		}
+		count += idx.countAtMost(room)
+	}
+	shared.Logger.Info("Fitting pairs counted.", "lock count", len(locks), "count", count)
+	return count
+}
+
+func parseSchematic(block []string) schematic {
+	lock := strings.Trim(block[0], "#") == ""
+	key := strings.Trim(block[len(block)-1], "#") == ""
+	if lock == key {
+		panic(fmt.Sprintf("Neither lock nor key: %v.", block))
+	}
+	heights := make([]int, len(block[0]))
+	for _, row := range block[1 : len(block)-1] {
+		for i, c := range row {
+			if c == '#' {
+				heights[i]++
+			}
+		}
+	}
+	return schematic{lock: lock, heights: heights}
+}
+
+// profileIndex counts height profiles packed into integers where each column is a digit in base
+// maximum height + 1.
+type profileIndex struct {
+	base   int
+	width  int
+	counts []int
+}
+
+func newProfileIndex(width, maxHeight int) *profileIndex {
+	base := maxHeight + 1
+	return &profileIndex{
+		base:   base,
+		width:  width,
+		counts: make([]int, shared.Pow(base, width)),
+	}
+}
+
+func (v *profileIndex) pack(heights []int) int {
+	packed := 0
+	for _, each := range heights {
+		packed = packed*v.base + each
+	}
+	return packed
+}
+
+func (v *profileIndex) add(heights []int) {
+	v.counts[v.pack(heights)]++
+}
+
+// accumulate turns the counts into cumulative counts so that each profile holds the number of
+// profiles that are at most as high in every column.
+func (v *profileIndex) accumulate() {
+	stride := 1
+	for range v.width {
+		for packed := range v.counts {
+			if (packed/stride)%v.base > 0 {
+				v.counts[packed] += v.counts[packed-stride]
+			}
+		}
+		stride *= v.base
+	}
+}
+
+func (v *profileIndex) countAtMost(heights []int) int {
+	return v.counts[v.pack(heights)]
+}
@@ -0,0 +1,56 @@
+package aoc2425
+
+import (
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/stretchr/testify/require"
+)
+
+var example = []string{
+	"#####", ".####", ".####", ".####", ".#.#.", ".#...", ".....",
+	"",
+	"#####", "##.##", ".#.##", "...##", "...#.", "...#.", ".....",
+	"",
+	".....", "#....", "#....", "#...#", "#.#.#", "#.###", "#####",
+	"",
+	".....", ".....", "#.#..", "###..", "###.#", "###.#", "#####",
+	"",
+	".....", ".....", ".....", "#....", "#.#..", "#.#.#", "#####",
+}
+
+func TestCountFittingPairs(t *testing.T) {
+	run := func(name string, lines []string, expected int) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+
+			// EXERCISE & VERIFY
+			require.Equal(t, expected, CountFittingPairs(lines))
+		})
+	}
+
+	run("empty", []string{}, 0)
+	run("example", example, 3)
+}
+
+func TestParseSchematic(t *testing.T) {
+	blocks := shared.SplitToBlocks(example)
+	req := require.New(t)
+	req.Equal(schematic{lock: true, heights: []int{0, 5, 3, 4, 3}}, parseSchematic(blocks[0]))
+	req.Equal(schematic{lock: false, heights: []int{5, 0, 2, 1, 3}}, parseSchematic(blocks[2]))
+	req.Panics(func() { parseSchematic([]string{".....", "....."}) })
+}
+
+func TestProfileIndex(t *testing.T) {
+	req := require.New(t)
+	idx := newProfileIndex(2, 2)
+	for _, each := range [][]int{{0, 0}, {1, 2}, {2, 1}, {1, 1}, {1, 1}} {
+		idx.add(each)
+	}
+	idx.accumulate()
+	req.Equal(1, idx.countAtMost([]int{0, 0}))
+	req.Equal(1, idx.countAtMost([]int{0, 2}))
+	req.Equal(3, idx.countAtMost([]int{1, 1}))
+	req.Equal(4, idx.countAtMost([]int{1, 2}))
+	req.Equal(5, idx.countAtMost([]int{2, 2}))
+}
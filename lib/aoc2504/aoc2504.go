@@ -2,8 +2,13 @@ package aoc2504
 
 import "github.com/denarced/advent-of-code/shared"
 
+// FrameCb receives the board before the first removal round and after each of them when set.
+// Removed rolls are marked with "x".
+var FrameCb func(brd *shared.Board)
+
 func CountRolls(lines []string, tries int) int {
 	board := shared.NewBoard(lines)
+	recordFrame(board)
 	var count int
 	for i := tries; i != 0; i-- {
 		prev := count
@@ -15,10 +20,17 @@ func CountRolls(lines []string, tries int) int {
 		if prev == count {
 			break
 		}
+		recordFrame(board)
 	}
 	return count
 }
 
+func recordFrame(board *shared.Board) {
+	if FrameCb != nil {
+		FrameCb(board)
+	}
+}
+
 func deriveMovableRolls(board *shared.Board) []shared.Loc {
 	var locs []shared.Loc
 	board.Iter(func(loc shared.Loc, c rune) bool {
@@ -19,3 +19,34 @@ func TestCountRolls(t *testing.T) {
 	ass.Equal(13, CountRolls(lines, 1))
 	ass.Equal(43, CountRolls(lines, -1))
 }
+
+func TestCountRollsFrames(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	var frames [][]string
+	FrameCb = func(brd *shared.Board) {
+		frames = append(frames, brd.GetLines())
+	}
+	defer func() {
+		FrameCb = nil
+	}()
+	lines := []string{
+		"@@@",
+		"@@@",
+		"@@@",
+	}
+
+	// EXERCISE
+	count := CountRolls(lines, -1)
+
+	// VERIFY
+	req.Equal(9, count)
+	req.Equal(
+		[][]string{
+			lines,
+			{"x@x", "@@@", "x@x"},
+			{"xxx", "x@x", "xxx"},
+			{"xxx", "xxx", "xxx"},
+		},
+		frames)
+}
@@ -3,7 +3,6 @@ package aoc2511
 import (
 	"fmt"
 	"math/rand/v2"
-	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -26,53 +25,35 @@ func TestCountPaths(t *testing.T) {
 		shared.InitTestLogging(t)
 		req := require.New(t)
 		lines := readTestData(req, "in.txt")
-		count := CountPaths(lines, "you")
-		req.Equal(5, count)
+		count, err := CountPaths(lines, "you", "out")
+		req.NoError(err)
+		req.Equal(int64(5), count.Int64())
 	})
 
 	t.Run("svr", func(t *testing.T) {
 		shared.InitTestLogging(t)
 		req := require.New(t)
 		lines := readTestData(req, "in2.txt")
-		count := CountPaths(lines, "svr")
-		req.Equal(2, count)
+		count, err := CountPaths(lines, "svr", "out", "fft", "dac")
+		req.NoError(err)
+		req.Equal(int64(2), count.Int64())
 	})
-}
 
-func TestSpider(t *testing.T) {
-	shared.InitTestLogging(t)
-	req := require.New(t)
-	lines, err := inr.ReadPath("testdata/in2.txt")
-	req.NoError(err, "failed to read test data")
-	req.NotEmpty(lines, "lines are empty")
-	points := parseTree(lines)
-	webIt(points)
-	for _, each := range []struct {
-		name    string
-		asshole bool
-		devil   bool
-	}{
-		{"aaa", false, true},
-		{"bbb", false, true},
-		{"ccc", true, true},
-		{"dac", true, false},
-		{"ddd", true, false},
-		{"eee", true, true},
-		{"fff", true, false},
-		{"fft", false, true},
-		{"ggg", true, false},
-		{"hhh", true, false},
-		{"hub", true, false},
-		{"out", true, false},
-		{"tty", false, true},
-	} {
-		bobo := points[each.name]
-		req.Equalf(each.asshole, bobo.foundByAsshole, "%s asshole", each.name)
-		req.Equalf(each.devil, bobo.foundByDevil, "%s devil", each.name)
-	}
+	t.Run("cycle", func(t *testing.T) {
+		shared.InitTestLogging(t)
+		req := require.New(t)
+		_, err := CountPaths([]string{"you: aaa", "aaa: bbb", "bbb: aaa out"}, "you", "out")
+		req.EqualError(err, "cycle found: aaa -> bbb -> aaa")
+	})
 }
 
 func TestSvr(t *testing.T) {
+	countSvr := func(t *testing.T, lines []string) int {
+		count, err := CountPaths(lines, "svr", "out", "fft", "dac")
+		require.NoError(t, err)
+		return int(count.Int64())
+	}
+
 	t.Run("mess", func(t *testing.T) {
 		shared.InitTestLogging(t)
 		lines := []string{
@@ -94,7 +75,7 @@ func TestSvr(t *testing.T) {
 			"mmm: ooo ppp",
 			"nnn: qqq rrr",
 		}
-		count := CountPaths(lines, "svr")
+		count := countSvr(t, lines)
 		req := require.New(t)
 		// svr -> fft: 2 ways
 		//     svr -> fft
@@ -117,7 +98,7 @@ func TestSvr(t *testing.T) {
 			"dac: out",
 			"out: zzz",
 		}
-		count := CountPaths(lines, "svr")
+		count := countSvr(t, lines)
 		req := require.New(t)
 		req.Equal(1, count)
 	})
@@ -130,7 +111,7 @@ func TestSvr(t *testing.T) {
 			"dac: out ddd dde",
 			"out: zzz",
 		}
-		count := CountPaths(lines, "svr")
+		count := countSvr(t, lines)
 		req := require.New(t)
 		req.Equal(1, count)
 	})
@@ -149,7 +130,7 @@ func TestSvr(t *testing.T) {
 			"eee: out",
 			"fff: out",
 		}
-		count := CountPaths(lines, "svr")
+		count := countSvr(t, lines)
 		req := require.New(t)
 		req.Equal(8, count)
 	})
@@ -181,7 +162,7 @@ func TestSvr(t *testing.T) {
 		lines = generateLines(lines, "aaa", "out", "hhh", 10)
 		lines = generateLines(lines, "svr", "out", "hih", 10)
 
-		count := CountPaths(lines, "svr")
+		count := countSvr(t, lines)
 		req := require.New(t)
 		req.Equal(100*100*100, count)
 	})
@@ -202,7 +183,7 @@ func TestSvr(t *testing.T) {
 			"eee: out",
 			"eef: out",
 		}
-		count := CountPaths(lines, "svr")
+		count := countSvr(t, lines)
 		req.Equal(8, count)
 	})
 }
@@ -284,89 +265,33 @@ func ss(s ...string) []string {
 	return s
 }
 
-func TestCombDefect(t *testing.T) {
+// countNaively counts paths by walking every one of them.
+func countNaively(edges []shared.Pair[string], from, to string) int {
+	if from == to {
+		return 1
+	}
+	count := 0
+	for _, each := range edges {
+		if each.First == from {
+			count += countNaively(edges, each.Second, to)
+		}
+	}
+	return count
+}
+
+func TestCountPathsRandomly(t *testing.T) {
 	run := func(name string, lines []string) {
 		t.Run(name, func(t *testing.T) {
 			shared.InitTestLogging(t)
 			req := require.New(t)
-			gen := func() (map[string]*Point, *Point, *Point) {
-				fats := append([]string{"aaa: svr"}, lines...)
-				fats = append(fats, "fft: dac", "dac: out", "out: zzz")
-				parsed := parseTree(fats)
-				return parsed, parsed["svr"], parsed["fft"]
-			}
-			var counts []int
+			expected := countNaively(parseEdges(lines), "svr", "fft")
 
-			_, svr, fft := gen()
-			weblessCounter := newCounter()
 			// EXERCISE
-			comb(fft, svr, false, nil, weblessCounter)
-			counts = append(counts, weblessCounter.count)
-
-			_, svr, fft = gen()
-			weblessReverseCounter := newCounter()
-			// EXERCISE
-			comb(svr, fft, true, nil, weblessReverseCounter)
-			counts = append(counts, weblessReverseCounter.count)
-
-			logAllowed := func(allowed *gent.Set[*Point]) {
-				if shared.IsDebugEnabled() {
-					expr := gent.Map(allowed.ToSlice(), func(p *Point) string {
-						var parents, kids string
-						for each := range p.parents {
-							if parents != "" {
-								parents += " "
-							}
-							parents += each
-						}
-						for each := range p.kids {
-							if kids != "" {
-								kids += " "
-							}
-							kids += each
-						}
-						return "<" + parents + " + " + kids + ">"
-					})
-					slices.Sort(expr)
-					shared.Logger.Debug(
-						"About to comb.",
-						"case",
-						"webful",
-						"allowed",
-						expr,
-					)
-				}
-			}
-
-			parsed, svr, fft := gen()
-			web(svr, fft)
-			webfulCounter := newCounter()
-			allowed := FilterDoubles(parsed)
-			logAllowed(allowed)
-			// EXERCISE
-			comb(fft, svr, false, allowed, webfulCounter)
-			counts = append(counts, webfulCounter.count)
-
-			parsed, svr, fft = gen()
-			web(svr, fft)
-			webfulReverseCounter := newCounter()
-			allowed = FilterDoubles(parsed)
-			logAllowed(allowed)
-			// EXERCISE
-			comb(svr, fft, true, allowed, webfulReverseCounter)
-			counts = append(counts, webfulReverseCounter.count)
+			actual, err := CountPaths(lines, "svr", "fft")
 
 			// VERIFY
-			firstCount := counts[0]
-			for i := 1; i < len(counts); i++ {
-				req.Equalf(
-					firstCount,
-					counts[i],
-					"count mismatch: %s, %s",
-					counts,
-					lines,
-				)
-			}
+			req.NoError(err)
+			req.Equalf(int64(expected), actual.Int64(), "count mismatch: %s", lines)
 		})
 	}
 
@@ -385,7 +310,6 @@ func TestCombDefect(t *testing.T) {
 			"eee: fft",
 		))
 
-	// This was an aid to find a defect in web function.
 	gen := &generator{}
 	for i := range 500 {
 		lines := gen.generate()
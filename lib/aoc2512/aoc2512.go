@@ -4,26 +4,30 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/denarced/advent-of-code/shared"
 )
 
+const defaultBudget = 10 * time.Second
+
+// CountFittingRegions counts the regions where the presents fit. Regions that can't be resolved
+// within the default budget aren't counted.
 func CountFittingRegions(lines []string) (int, error) {
-	var fittingCount int
-	presents, regions, err := parseLines(lines)
+	results, err := SolveRegions(lines, defaultBudget)
 	if err != nil {
 		return 0, err
 	}
-	for _, each := range regions {
-		availableSpots := each.width * each.height
-		for i, mul := range each.counts {
-			if mul == 0 {
-				continue
-			}
-			availableSpots -= mul * presents[i].spots
-		}
-		if availableSpots >= 0 {
+	var fittingCount, unknownCount int
+	for _, each := range results {
+		switch each.Verdict {
+		case Fits:
 			fittingCount++
+		case Unknown:
+			unknownCount++
 		}
 	}
+	shared.Logger.Info("Regions counted.", "fitting", fittingCount, "unknown", unknownCount)
 	return fittingCount, nil
 }
 
@@ -1,17 +1,21 @@
 package aoc2512
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/denarced/advent-of-code/shared"
 	"github.com/denarced/advent-of-code/shared/inr"
+	"github.com/denarced/gent"
 	"github.com/stretchr/testify/require"
 )
 
-func _TestCountFittingRegions(t *testing.T) {
+func TestCountFittingRegions(t *testing.T) {
 	shared.InitTestLogging(t)
 	req := require.New(t)
-	lines, err := inr.ReadPath("testdata/in.txt")
+	lines, err := inr.ReadPath("testdata/in.txt", inr.IncludeEmpty())
 	req.NoError(err, "failed to read test data")
 	// EXERCISE
 	count, err := CountFittingRegions(lines)
@@ -67,3 +71,129 @@ func TestParseLines(t *testing.T) {
 		},
 		regions)
 }
+
+func TestSolveRegions(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	lines, err := inr.ReadPath("testdata/in.txt", inr.IncludeEmpty())
+	req.NoError(err, "failed to read test data")
+	presents, regions, err := parseLines(lines)
+	req.NoError(err)
+
+	// EXERCISE
+	results, err := SolveRegions(lines, time.Minute)
+
+	// VERIFY
+	req.NoError(err)
+	req.Equal(
+		[]Verdict{Fits, Fits, DoesNotFit},
+		gent.Map(results, func(r RegionResult) Verdict { return r.Verdict }),
+	)
+	for i, each := range results[:2] {
+		t.Logf("Region %d:\n%s", i, strings.Join(each.Placement, "\n"))
+		verifyPlacement(t, presents, regions[i], each.Placement)
+	}
+	req.Nil(results[2].Placement)
+}
+
+// verifyPlacement verifies that placement has a piece for each present and that the pieces have
+// the right shape.
+func verifyPlacement(t *testing.T, presents []present, reg region, placement []string) {
+	req := require.New(t)
+	req.Len(placement, reg.height)
+	pieces := map[rune][]cell{}
+	for y, row := range placement {
+		req.Len(row, reg.width)
+		for x, c := range row {
+			if c != '.' {
+				pieces[c] = append(pieces[c], cell{x: x, y: y})
+			}
+		}
+	}
+	// Count the pieces per present by looking each piece up from the orientations.
+	orientationToPresent := map[string]int{}
+	for i := range reg.counts {
+		for _, each := range presents[i].orientations() {
+			orientationToPresent[fmt.Sprint(each)] = i
+		}
+	}
+	actual := make([]int, len(reg.counts))
+	for _, each := range pieces {
+		i, ok := orientationToPresent[fmt.Sprint(normalize(each))]
+		req.Truef(ok, "unknown piece: %v", each)
+		actual[i]++
+	}
+	req.Equal(reg.counts, actual)
+}
+
+func TestPackRegion(t *testing.T) {
+	run := func(name string, reg region, expected Verdict, expectedPlacement []string) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			presents := []present{
+				parsePresentLines([]string{"0:", "##", "#."}),
+				parsePresentLines([]string{"1:", "##", "##"}),
+			}
+
+			// EXERCISE
+			result := packRegion(presents, reg, time.Minute)
+
+			// VERIFY
+			req.Equal(expected, result.Verdict)
+			req.Equal(expectedPlacement, result.Placement)
+		})
+	}
+
+	run("nothing", region{width: 2, height: 1, counts: []int{0, 0}}, Fits, []string{".."})
+	run("too small", region{width: 2, height: 1, counts: []int{1, 0}}, DoesNotFit, nil)
+	run("blocks", region{width: 4, height: 2, counts: []int{1, 1}}, Fits, []string{"AABB", "A.BB"})
+	run(
+		"rotated",
+		region{width: 3, height: 2, counts: []int{2, 0}},
+		Fits,
+		[]string{"AAB", "ABB"},
+	)
+	run("no room to rotate", region{width: 4, height: 1, counts: []int{1, 0}}, DoesNotFit, nil)
+	run("area fits but shape doesn't", region{width: 3, height: 3, counts: []int{0, 2}}, DoesNotFit, nil)
+}
+
+func TestPackRegionTimeout(t *testing.T) {
+	shared.InitTestLogging(t)
+	presents := []present{parsePresentLines([]string{"0:", "#"})}
+	// Solvable but the budget is spent before the search starts.
+	result := packRegion(presents, region{width: 1, height: 1, counts: []int{1}}, -time.Second)
+	require.Equal(t, Fits, result.Verdict, "block placement doesn't need a search")
+
+	aPacker := &packer{
+		width:        1,
+		height:       2,
+		orientations: [][][]cell{presents[0].orientations()},
+		remaining:    []int{1},
+		grid:         []int{-1, -1},
+		slack:        1,
+		steps:        9_999,
+	}
+	require.False(t, aPacker.solve(0))
+	require.True(t, aPacker.timedOut)
+}
+
+func TestOrientations(t *testing.T) {
+	run := func(name string, table []string, expected int) {
+		t.Run(name, func(t *testing.T) {
+			actual := present{table: table}.orientations()
+			require.Len(t, actual, expected)
+			for _, each := range actual {
+				require.Equal(t, cell{}, each[0], "anchor is the first cell")
+			}
+		})
+	}
+
+	run("single", []string{"#"}, 1)
+	run("square", []string{"##", "##"}, 1)
+	run("line", []string{"###"}, 2)
+	run("corner", []string{"##", "#."}, 4)
+	run("s", []string{".##", "##."}, 4)
+	run("l", []string{"#.", "#.", "##"}, 8)
+	run("f", []string{".##", "##.", ".#."}, 8)
+}
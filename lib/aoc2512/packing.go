@@ -0,0 +1,296 @@
+package aoc2512
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/denarced/advent-of-code/shared"
+)
+
+type Verdict int
+
+const (
+	Unknown Verdict = iota
+	Fits
+	DoesNotFit
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Fits:
+		return "fits"
+	case DoesNotFit:
+		return "doesn't fit"
+	default:
+		return "unknown"
+	}
+}
+
+// RegionResult is the verdict for a region and, when the presents fit, the placement. Each
+// present in the placement is marked with its own letter and empty cells with '.'.
+type RegionResult struct {
+	Verdict   Verdict
+	Placement []string
+}
+
+// cell is a cell in an orientation of a present, relative to the orientation's anchor which is
+// its first cell in row-major order.
+type cell struct {
+	x, y int
+}
+
+// orientations returns the distinct rotations and reflections of the present.
+func (v present) orientations() [][]cell {
+	var base []cell
+	for y, row := range v.table {
+		for x, c := range row {
+			if c == '#' {
+				base = append(base, cell{x: x, y: y})
+			}
+		}
+	}
+	seen := map[string]bool{}
+	var result [][]cell
+	current := base
+	for range 2 {
+		for range 4 {
+			normalized := normalize(current)
+			key := fmt.Sprint(normalized)
+			if !seen[key] {
+				seen[key] = true
+				result = append(result, normalized)
+			}
+			// Rotate 90 degrees.
+			current = mapCells(current, func(c cell) cell { return cell{x: -c.y, y: c.x} })
+		}
+		// Reflect.
+		current = mapCells(current, func(c cell) cell { return cell{x: -c.x, y: c.y} })
+	}
+	return result
+}
+
+func mapCells(cells []cell, f func(cell) cell) []cell {
+	mapped := make([]cell, len(cells))
+	for i, each := range cells {
+		mapped[i] = f(each)
+	}
+	return mapped
+}
+
+// normalize sorts the cells in row-major order and moves them so that the first one is at 0,0.
+func normalize(cells []cell) []cell {
+	sorted := slices.Clone(cells)
+	slices.SortFunc(sorted, func(a, b cell) int {
+		if a.y != b.y {
+			return a.y - b.y
+		}
+		return a.x - b.x
+	})
+	first := sorted[0]
+	return mapCells(sorted, func(c cell) cell { return cell{x: c.x - first.x, y: c.y - first.y} })
+}
+
+type packer struct {
+	width, height int
+	// orientations[i] are the orientations of present i.
+	orientations [][][]cell
+	remaining    []int
+	// Grid cells, -1 when empty, otherwise the index of the piece.
+	grid       []int
+	pieceCount int
+	// Number of cells that can be left empty.
+	slack    int
+	deadline time.Time
+	steps    int
+	timedOut bool
+}
+
+// solve covers the first empty cell at or after index with a present or leaves it empty.
+func (v *packer) solve(index int) bool {
+	v.steps++
+	if v.steps%10_000 == 0 && time.Now().After(v.deadline) {
+		v.timedOut = true
+	}
+	if v.timedOut {
+		return false
+	}
+	for index < len(v.grid) && v.grid[index] >= 0 {
+		index++
+	}
+	if !slices.ContainsFunc(v.remaining, func(count int) bool { return count > 0 }) {
+		return true
+	}
+	if index >= len(v.grid) {
+		return false
+	}
+	x, y := index%v.width, index/v.width
+	for i, count := range v.remaining {
+		if count == 0 {
+			continue
+		}
+		for _, each := range v.orientations[i] {
+			if !v.canPlace(x, y, each) {
+				continue
+			}
+			v.place(x, y, each, v.pieceCount)
+			v.pieceCount++
+			v.remaining[i]--
+			if v.solve(index + 1) {
+				return true
+			}
+			v.remaining[i]++
+			v.pieceCount--
+			v.place(x, y, each, -1)
+		}
+	}
+	if v.slack == 0 {
+		return false
+	}
+	// Leave the cell empty. It's marked so that it's not picked again.
+	v.slack--
+	v.grid[index] = len(v.grid)
+	if v.solve(index + 1) {
+		return true
+	}
+	v.grid[index] = -1
+	v.slack++
+	return false
+}
+
+func (v *packer) canPlace(x, y int, cells []cell) bool {
+	for _, each := range cells {
+		cx, cy := x+each.x, y+each.y
+		if cx < 0 || cy < 0 || cx >= v.width || cy >= v.height || v.grid[cy*v.width+cx] >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (v *packer) place(x, y int, cells []cell, piece int) {
+	for _, each := range cells {
+		v.grid[(y+each.y)*v.width+x+each.x] = piece
+	}
+}
+
+func (v *packer) render() []string {
+	rows := make([]string, 0, v.height)
+	for y := range v.height {
+		var row strings.Builder
+		for x := range v.width {
+			piece := v.grid[y*v.width+x]
+			if piece < 0 || piece >= len(v.grid) {
+				row.WriteRune('.')
+			} else {
+				row.WriteRune(rune('A' + piece%26))
+			}
+		}
+		rows = append(rows, row.String())
+	}
+	return rows
+}
+
+// packRegion finds out whether the presents fit in the region. The area is checked first, then
+// whether every present fits in its own block. Only then the presents are searched for an exact
+// placement, for at most budget.
+func packRegion(presents []present, reg region, budget time.Duration) RegionResult {
+	if len(reg.counts) > len(presents) {
+		panic(fmt.Sprintf("Region refers to unknown presents: %v.", reg.counts))
+	}
+	area := reg.width * reg.height
+	needed := 0
+	total := 0
+	blockWidth, blockHeight := 0, 0
+	for i, count := range reg.counts {
+		needed += count * presents[i].spots
+		total += count
+		if count > 0 {
+			blockHeight = shared.Max(blockHeight, len(presents[i].table))
+			for _, row := range presents[i].table {
+				blockWidth = shared.Max(blockWidth, len(row))
+			}
+		}
+	}
+	if needed > area {
+		return RegionResult{Verdict: DoesNotFit}
+	}
+	aPacker := &packer{
+		width:     reg.width,
+		height:    reg.height,
+		remaining: slices.Clone(reg.counts),
+		grid:      make([]int, area),
+		slack:     area - needed,
+		deadline:  time.Now().Add(budget),
+	}
+	for i := range aPacker.grid {
+		aPacker.grid[i] = -1
+	}
+	if total == 0 {
+		return RegionResult{Verdict: Fits, Placement: aPacker.render()}
+	}
+	if blockWidth > 0 && total <= (reg.width/blockWidth)*(reg.height/blockHeight) {
+		aPacker.placeInBlocks(presents, blockWidth, blockHeight)
+		return RegionResult{Verdict: Fits, Placement: aPacker.render()}
+	}
+
+	for i, each := range presents {
+		if i < len(reg.counts) {
+			aPacker.orientations = append(aPacker.orientations, each.orientations())
+		}
+	}
+	if aPacker.solve(0) {
+		return RegionResult{Verdict: Fits, Placement: aPacker.render()}
+	}
+	if aPacker.timedOut {
+		return RegionResult{Verdict: Unknown}
+	}
+	return RegionResult{Verdict: DoesNotFit}
+}
+
+// placeInBlocks places each present as is in its own block of the region.
+func (v *packer) placeInBlocks(presents []present, blockWidth, blockHeight int) {
+	columns := v.width / blockWidth
+	block := 0
+	for i, count := range v.remaining {
+		for range count {
+			x := (block % columns) * blockWidth
+			y := (block / columns) * blockHeight
+			for dy, row := range presents[i].table {
+				for dx, c := range row {
+					if c == '#' {
+						v.grid[(y+dy)*v.width+x+dx] = v.pieceCount
+					}
+				}
+			}
+			v.pieceCount++
+			block++
+		}
+		v.remaining[i] = 0
+	}
+}
+
+// SolveRegions finds out for each region whether the presents fit in it. Each region is searched
+// for at most budget.
+func SolveRegions(lines []string, budget time.Duration) ([]RegionResult, error) {
+	presents, regions, err := parseLines(lines)
+	if err != nil {
+		return nil, err
+	}
+	shared.Logger.Info(
+		"Solve regions.",
+		"present count",
+		len(presents),
+		"region count",
+		len(regions),
+		"budget",
+		budget,
+	)
+	results := make([]RegionResult, len(regions))
+	for i, each := range regions {
+		results[i] = packRegion(presents, each, budget)
+		shared.Logger.Debug("Region solved.", "region", each, "verdict", results[i].Verdict)
+	}
+	return results, nil
+}
@@ -0,0 +1,216 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/denarced/advent-of-code/shared"
+)
+
+// Diagram is a graph to look at: nodes have kinds, edges have labels such as weights, and paths or
+// cuts can be highlighted. It's exported as Graphviz DOT or Mermaid text.
+type Diagram struct {
+	Directed bool
+	nodes    []diagramNode
+	edges    []diagramEdge
+	indexes  map[string]int
+	kinds    []string
+}
+
+type diagramNode struct {
+	id          string
+	kind        string
+	highlighted bool
+}
+
+type diagramEdge struct {
+	from, to    string
+	label       string
+	highlighted bool
+	cut         bool
+}
+
+// shape is how a node kind is drawn. Kinds get shapes in the order they're added.
+type shape struct {
+	dot                       string
+	mermaidOpen, mermaidClose string
+}
+
+var shapes = []shape{
+	{dot: "ellipse", mermaidOpen: "([", mermaidClose: "])"},
+	{dot: "box", mermaidOpen: "[", mermaidClose: "]"},
+	{dot: "diamond", mermaidOpen: "{", mermaidClose: "}"},
+	{dot: "hexagon", mermaidOpen: "{{", mermaidClose: "}}"},
+	{dot: "doublecircle", mermaidOpen: "((", mermaidClose: "))"},
+	{dot: "parallelogram", mermaidOpen: "[/", mermaidClose: "/]"},
+}
+
+func NewDiagram(directed bool) *Diagram {
+	return &Diagram{Directed: directed, indexes: map[string]int{}}
+}
+
+// AddNode adds a node or changes the kind of an existing one.
+func (v *Diagram) AddNode(id, kind string) {
+	if !slices.Contains(v.kinds, kind) {
+		v.kinds = append(v.kinds, kind)
+	}
+	if i, ok := v.indexes[id]; ok {
+		v.nodes[i].kind = kind
+		return
+	}
+	v.indexes[id] = len(v.nodes)
+	v.nodes = append(v.nodes, diagramNode{id: id, kind: kind})
+}
+
+// AddEdge adds an edge. Nodes that haven't been added get an empty kind.
+func (v *Diagram) AddEdge(from, to, label string) {
+	for _, each := range []string{from, to} {
+		if _, ok := v.indexes[each]; !ok {
+			v.AddNode(each, "")
+		}
+	}
+	v.edges = append(v.edges, diagramEdge{from: from, to: to, label: label})
+}
+
+// HighlightPath highlights the nodes and the edges between consecutive nodes.
+func (v *Diagram) HighlightPath(ids ...string) error {
+	for _, each := range ids {
+		i, ok := v.indexes[each]
+		if !ok {
+			return fmt.Errorf("unknown node: %s", each)
+		}
+		v.nodes[i].highlighted = true
+	}
+	for i := 1; i < len(ids); i++ {
+		edge, ok := v.findEdge(ids[i-1], ids[i])
+		if !ok {
+			return fmt.Errorf("no edge from %s to %s", ids[i-1], ids[i])
+		}
+		edge.highlighted = true
+	}
+	return nil
+}
+
+// HighlightCut marks edges as cut.
+func (v *Diagram) HighlightCut(edges ...shared.Pair[string]) error {
+	for _, each := range edges {
+		edge, ok := v.findEdge(each.First, each.Second)
+		if !ok {
+			return fmt.Errorf("no edge from %s to %s", each.First, each.Second)
+		}
+		edge.cut = true
+	}
+	return nil
+}
+
+func (v *Diagram) findEdge(from, to string) (*diagramEdge, bool) {
+	for i := range v.edges {
+		each := &v.edges[i]
+		if each.from == from && each.to == to {
+			return each, true
+		}
+		if !v.Directed && each.from == to && each.to == from {
+			return each, true
+		}
+	}
+	return nil, false
+}
+
+func (v *Diagram) shapeOf(kind string) shape {
+	for i, each := range v.kinds {
+		if each == kind {
+			return shapes[i%len(shapes)]
+		}
+	}
+	return shapes[0]
+}
+
+func (v *Diagram) label(node diagramNode) string {
+	if node.kind == "" {
+		return node.id
+	}
+	return fmt.Sprintf("%s (%s)", node.id, node.kind)
+}
+
+// DOT exports the diagram in Graphviz DOT format.
+func (v *Diagram) DOT() string {
+	var b strings.Builder
+	arrow := "--"
+	if v.Directed {
+		b.WriteString("digraph {\n")
+		arrow = "->"
+	} else {
+		b.WriteString("graph {\n")
+	}
+	for _, each := range v.nodes {
+		attrs := []string{
+			fmt.Sprintf("label=%q", v.label(each)),
+			"shape=" + v.shapeOf(each.kind).dot,
+		}
+		if each.highlighted {
+			attrs = append(attrs, "color=red", "penwidth=2")
+		}
+		fmt.Fprintf(&b, "    %q [%s];\n", each.id, strings.Join(attrs, ", "))
+	}
+	for _, each := range v.edges {
+		var attrs []string
+		if each.label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", each.label))
+		}
+		if each.highlighted {
+			attrs = append(attrs, "color=red", "penwidth=2")
+		}
+		if each.cut {
+			attrs = append(attrs, "color=blue", "style=dashed")
+		}
+		fmt.Fprintf(&b, "    %q %s %q", each.from, arrow, each.to)
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attrs, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid exports the diagram as a Mermaid flowchart. Node IDs are replaced with n0, n1, ... since
+// Mermaid is picky about them.
+func (v *Diagram) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	var highlighted []string
+	for i, each := range v.nodes {
+		s := v.shapeOf(each.kind)
+		fmt.Fprintf(&b, "    n%d%s\"%s\"%s\n", i, s.mermaidOpen, v.label(each), s.mermaidClose)
+		if each.highlighted {
+			highlighted = append(highlighted, fmt.Sprintf("n%d", i))
+		}
+	}
+	arrow := shared.Or(v.Directed, "-->", "---")
+	var linkStyles []string
+	for i, each := range v.edges {
+		from, to := v.indexes[each.from], v.indexes[each.to]
+		if each.label != "" {
+			fmt.Fprintf(&b, "    n%d %s|\"%s\"| n%d\n", from, arrow, each.label, to)
+		} else {
+			fmt.Fprintf(&b, "    n%d %s n%d\n", from, arrow, to)
+		}
+		if each.highlighted {
+			linkStyles = append(linkStyles, fmt.Sprintf(
+				"    linkStyle %d stroke:#e03030,stroke-width:3px\n", i))
+		}
+		if each.cut {
+			linkStyles = append(linkStyles, fmt.Sprintf(
+				"    linkStyle %d stroke:#4070f0,stroke-dasharray:5\n", i))
+		}
+	}
+	for _, each := range linkStyles {
+		b.WriteString(each)
+	}
+	if len(highlighted) > 0 {
+		b.WriteString("    classDef highlight stroke:#e03030,stroke-width:3px\n")
+		fmt.Fprintf(&b, "    class %s highlight\n", strings.Join(highlighted, ","))
+	}
+	return b.String()
+}
@@ -0,0 +1,479 @@
+// Package graph contains graph routines shared by the lib modules.
+package graph
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+	"slices"
+	"strings"
+
+	"github.com/denarced/advent-of-code/shared"
+)
+
+// ParseEdges parses lines such as "kh-tc" to edges.
+func ParseEdges(lines []string) []shared.Pair[string] {
+	var edges []shared.Pair[string]
+	for _, each := range lines {
+		trimmed := strings.TrimSpace(each)
+		if trimmed == "" {
+			continue
+		}
+		pieces := strings.Split(trimmed, "-")
+		if len(pieces) != 2 {
+			panic(fmt.Sprintf("Invalid edge: %s.", trimmed))
+		}
+		edges = append(
+			edges,
+			shared.NewPair(strings.TrimSpace(pieces[0]), strings.TrimSpace(pieces[1])),
+		)
+	}
+	return edges
+}
+
+type bitset []uint64
+
+func newBitset(size int) bitset {
+	return make(bitset, (size+63)/64)
+}
+
+func (v bitset) add(i int) {
+	v[i/64] |= 1 << (i % 64)
+}
+
+func (v bitset) remove(i int) {
+	v[i/64] &^= 1 << (i % 64)
+}
+
+func (v bitset) has(i int) bool {
+	return v[i/64]&(1<<(i%64)) != 0
+}
+
+func (v bitset) isEmpty() bool {
+	for _, each := range v {
+		if each != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (v bitset) and(other bitset) bitset {
+	result := make(bitset, len(v))
+	for i := range v {
+		result[i] = v[i] & other[i]
+	}
+	return result
+}
+
+func (v bitset) andNot(other bitset) bitset {
+	result := make(bitset, len(v))
+	for i := range v {
+		result[i] = v[i] &^ other[i]
+	}
+	return result
+}
+
+func (v bitset) or(other bitset) bitset {
+	result := make(bitset, len(v))
+	for i := range v {
+		result[i] = v[i] | other[i]
+	}
+	return result
+}
+
+func (v bitset) count() int {
+	count := 0
+	for _, each := range v {
+		count += bits.OnesCount64(each)
+	}
+	return count
+}
+
+// members returns the set bits in ascending order.
+func (v bitset) members() []int {
+	var members []int
+	for i, each := range v {
+		for each != 0 {
+			members = append(members, i*64+bits.TrailingZeros64(each))
+			each &= each - 1
+		}
+	}
+	return members
+}
+
+// Undirected is an undirected graph whose nodes are indexed in name order.
+type Undirected struct {
+	names     []string
+	indexes   map[string]int
+	neighbors []bitset
+}
+
+func NewUndirected(edges []shared.Pair[string]) *Undirected {
+	names, indexes := indexNames(edges)
+	neighbors := make([]bitset, len(names))
+	for i := range neighbors {
+		neighbors[i] = newBitset(len(names))
+	}
+	for _, each := range edges {
+		a, b := indexes[each.First], indexes[each.Second]
+		if a == b {
+			continue
+		}
+		neighbors[a].add(b)
+		neighbors[b].add(a)
+	}
+	return &Undirected{names: names, indexes: indexes, neighbors: neighbors}
+}
+
+// Names returns the node names in sorted order.
+func (v *Undirected) Names() []string {
+	return slices.Clone(v.names)
+}
+
+// Connected checks whether there's an edge between a and b.
+func (v *Undirected) Connected(a, b string) bool {
+	i, ok := v.indexes[a]
+	if !ok {
+		return false
+	}
+	j, ok := v.indexes[b]
+	if !ok {
+		return false
+	}
+	return v.neighbors[i].has(j)
+}
+
+// Triangles returns all sets of three connected nodes, each sorted by name.
+func (v *Undirected) Triangles() [][]string {
+	var triangles [][]string
+	for a := range v.names {
+		for _, b := range v.neighbors[a].members() {
+			if b <= a {
+				continue
+			}
+			for _, c := range v.neighbors[a].and(v.neighbors[b]).members() {
+				if c <= b {
+					continue
+				}
+				triangles = append(triangles, []string{v.names[a], v.names[b], v.names[c]})
+			}
+		}
+	}
+	return triangles
+}
+
+// MaximalCliques calls cb with each maximal clique, sorted by name. It's Bron–Kerbosch with
+// pivoting.
+func (v *Undirected) MaximalCliques(cb func(clique []string)) {
+	all := newBitset(len(v.names))
+	for i := range v.names {
+		all.add(i)
+	}
+	v.bronKerbosch(nil, all, newBitset(len(v.names)), cb)
+}
+
+func (v *Undirected) bronKerbosch(
+	clique []int,
+	candidates, excluded bitset,
+	cb func(clique []string),
+) {
+	if candidates.isEmpty() {
+		if excluded.isEmpty() {
+			cb(toNames(v.names, clique))
+		}
+		return
+	}
+	// Pick the pivot with most candidate neighbors to minimize branching.
+	pivot := -1
+	pivotCount := -1
+	for _, each := range candidates.or(excluded).members() {
+		if count := candidates.and(v.neighbors[each]).count(); count > pivotCount {
+			pivot = each
+			pivotCount = count
+		}
+	}
+	for _, each := range candidates.andNot(v.neighbors[pivot]).members() {
+		v.bronKerbosch(
+			append(slices.Clone(clique), each),
+			candidates.and(v.neighbors[each]),
+			excluded.and(v.neighbors[each]),
+			cb,
+		)
+		candidates.remove(each)
+		excluded.add(each)
+	}
+}
+
+// MaximumClique returns the largest clique sorted by name. Ties are broken by name order.
+func (v *Undirected) MaximumClique() []string {
+	var largest []string
+	v.MaximalCliques(func(clique []string) {
+		if len(clique) > len(largest) ||
+			len(clique) == len(largest) && slices.Compare(clique, largest) < 0 {
+			largest = clique
+		}
+	})
+	return largest
+}
+
+// toNames maps indexes to names. Indexes are in name order so ascending indexes are sorted names.
+func toNames(names []string, indexes []int) []string {
+	sorted := slices.Clone(indexes)
+	slices.Sort(sorted)
+	return toNamesInOrder(names, sorted)
+}
+
+// Directed is a directed graph whose nodes are indexed in name order.
+type Directed struct {
+	names   []string
+	indexes map[string]int
+	kids    [][]int
+}
+
+func NewDirected(edges []shared.Pair[string]) *Directed {
+	names, indexes := indexNames(edges)
+	kids := make([][]int, len(names))
+	for _, each := range edges {
+		from := indexes[each.First]
+		kids[from] = append(kids[from], indexes[each.Second])
+	}
+	// Sorted so that ties are resolved in name order.
+	for _, each := range kids {
+		slices.Sort(each)
+	}
+	return &Directed{names: names, indexes: indexes, kids: kids}
+}
+
+// Names returns the node names in sorted order.
+func (v *Directed) Names() []string {
+	return slices.Clone(v.names)
+}
+
+func (v *Directed) index(name string) (int, error) {
+	i, ok := v.indexes[name]
+	if !ok {
+		return 0, fmt.Errorf("no such node: %s", name)
+	}
+	return i, nil
+}
+
+// TopologicalOrder returns the nodes reachable from "from" so that each node comes before the
+// nodes it leads to. It's Kahn's algorithm. If the reachable nodes contain a cycle, the error
+// contains the cycle.
+func (v *Directed) TopologicalOrder(from string) ([]string, error) {
+	order, err := v.topologicalOrder(from)
+	if err != nil {
+		return nil, err
+	}
+	return toNamesInOrder(v.names, order), nil
+}
+
+func (v *Directed) topologicalOrder(from string) ([]int, error) {
+	start, err := v.index(from)
+	if err != nil {
+		return nil, err
+	}
+	reachable := newBitset(len(v.names))
+	reachable.add(start)
+	stack := []int{start}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, each := range v.kids[current] {
+			if !reachable.has(each) {
+				reachable.add(each)
+				stack = append(stack, each)
+			}
+		}
+	}
+
+	inDegrees := make([]int, len(v.names))
+	for _, each := range reachable.members() {
+		for _, kid := range v.kids[each] {
+			inDegrees[kid]++
+		}
+	}
+	var order []int
+	queue := []int{}
+	for _, each := range reachable.members() {
+		if inDegrees[each] == 0 {
+			queue = append(queue, each)
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		order = append(order, current)
+		for _, kid := range v.kids[current] {
+			inDegrees[kid]--
+			if inDegrees[kid] == 0 {
+				queue = append(queue, kid)
+			}
+		}
+	}
+	if len(order) < reachable.count() {
+		return nil, fmt.Errorf("cycle found: %s", strings.Join(v.findCycle(inDegrees), " -> "))
+	}
+	return order, nil
+}
+
+// findCycle finds a cycle among the nodes that Kahn's algorithm couldn't order, i.e. the ones that
+// still have incoming edges. Each of them has a parent among them so walking backwards must loop.
+func (v *Directed) findCycle(inDegrees []int) []string {
+	parents := make([]int, len(v.names))
+	for i := range parents {
+		parents[i] = -1
+	}
+	current := -1
+	for from, kids := range v.kids {
+		if inDegrees[from] == 0 {
+			continue
+		}
+		for _, kid := range kids {
+			if inDegrees[kid] > 0 {
+				parents[kid] = from
+				current = kid
+			}
+		}
+	}
+	steps := map[int]int{}
+	var walk []int
+	for {
+		if at, ok := steps[current]; ok {
+			cycle := walk[at:]
+			slices.Reverse(cycle)
+			// Start from the first name to make the result stable.
+			first := slices.Index(cycle, slices.Min(cycle))
+			cycle = slices.Concat(cycle[first:], cycle[:first])
+			names := toNamesInOrder(v.names, cycle)
+			return append(names, names[0])
+		}
+		steps[current] = len(walk)
+		walk = append(walk, current)
+		current = parents[current]
+	}
+}
+
+// CountPaths counts the paths from "from" to "to" that visit every waypoint, in any order.
+func (v *Directed) CountPaths(from, to string, waypoints ...string) (*big.Int, error) {
+	order, err := v.topologicalOrder(from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := v.index(to)
+	if err != nil {
+		return nil, err
+	}
+	// Each waypoint is a bit in the mask of visited waypoints.
+	bitsByNode := make([]int, len(v.names))
+	for i, each := range waypoints {
+		index, err := v.index(each)
+		if err != nil {
+			return nil, err
+		}
+		bitsByNode[index] |= 1 << i
+	}
+	full := 1<<len(waypoints) - 1
+
+	// counts[node][mask] is the number of paths from "from" to node visiting the waypoints in mask.
+	counts := make([][]*big.Int, len(v.names))
+	for _, each := range order {
+		counts[each] = make([]*big.Int, full+1)
+	}
+	start := v.indexes[from]
+	counts[start][bitsByNode[start]] = big.NewInt(1)
+	for _, current := range order {
+		for mask, count := range counts[current] {
+			if count == nil {
+				continue
+			}
+			for _, kid := range v.kids[current] {
+				kidMask := mask | bitsByNode[kid]
+				if counts[kid][kidMask] == nil {
+					counts[kid][kidMask] = new(big.Int)
+				}
+				counts[kid][kidMask].Add(counts[kid][kidMask], count)
+			}
+		}
+	}
+	if counts[end] == nil || counts[end][full] == nil {
+		return new(big.Int), nil
+	}
+	return counts[end][full], nil
+}
+
+// StronglyConnectedComponents returns the components in which every node can reach every other
+// node. It's Tarjan's algorithm. Names within a component are sorted and the components are sorted
+// by their first name.
+func (v *Directed) StronglyConnectedComponents() [][]string {
+	indexes := make([]int, len(v.names))
+	lows := make([]int, len(v.names))
+	onStack := newBitset(len(v.names))
+	var stack []int
+	var components [][]string
+	counter := 1
+	var connect func(node int)
+	connect = func(node int) {
+		indexes[node] = counter
+		lows[node] = counter
+		counter++
+		stack = append(stack, node)
+		onStack.add(node)
+		for _, kid := range v.kids[node] {
+			if indexes[kid] == 0 {
+				connect(kid)
+				lows[node] = min(lows[node], lows[kid])
+			} else if onStack.has(kid) {
+				lows[node] = min(lows[node], indexes[kid])
+			}
+		}
+		if lows[node] != indexes[node] {
+			return
+		}
+		var component []int
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack.remove(last)
+			component = append(component, last)
+			if last == node {
+				break
+			}
+		}
+		slices.Sort(component)
+		components = append(components, toNamesInOrder(v.names, component))
+	}
+	for i := range v.names {
+		if indexes[i] == 0 {
+			connect(i)
+		}
+	}
+	slices.SortFunc(components, func(a, b []string) int { return strings.Compare(a[0], b[0]) })
+	return components
+}
+
+func indexNames(edges []shared.Pair[string]) ([]string, map[string]int) {
+	indexes := map[string]int{}
+	for _, each := range edges {
+		indexes[each.First] = 0
+		indexes[each.Second] = 0
+	}
+	names := make([]string, 0, len(indexes))
+	for each := range indexes {
+		names = append(names, each)
+	}
+	slices.Sort(names)
+	for i, each := range names {
+		indexes[each] = i
+	}
+	return names, indexes
+}
+
+func toNamesInOrder(names []string, indexes []int) []string {
+	result := make([]string, len(indexes))
+	for i, each := range indexes {
+		result[i] = names[each]
+	}
+	return result
+}
@@ -0,0 +1,263 @@
+package graph
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEdges(t *testing.T) {
+	req := require.New(t)
+	req.Equal(
+		[]shared.Pair[string]{shared.NewPair("kh", "tc"), shared.NewPair("qp", "kh")},
+		ParseEdges([]string{"kh-tc", "", " qp-kh "}),
+	)
+	req.Nil(ParseEdges(nil))
+	req.Panics(func() { ParseEdges([]string{"kh"}) })
+}
+
+func TestBitset(t *testing.T) {
+	req := require.New(t)
+	a := newBitset(130)
+	req.True(a.isEmpty())
+	a.add(0)
+	a.add(64)
+	a.add(129)
+	req.Equal([]int{0, 64, 129}, a.members())
+	req.Equal(3, a.count())
+	b := newBitset(130)
+	b.add(64)
+	b.add(100)
+	req.Equal([]int{64}, a.and(b).members())
+	req.Equal([]int{0, 129}, a.andNot(b).members())
+	req.Equal([]int{0, 64, 100, 129}, a.or(b).members())
+	a.remove(64)
+	req.False(a.has(64))
+	req.True(a.has(129))
+}
+
+func TestMaximalCliques(t *testing.T) {
+	run := func(name string, lines []string, expected []string) {
+		t.Run(name, func(t *testing.T) {
+			shared.InitTestLogging(t)
+			req := require.New(t)
+			g := NewUndirected(ParseEdges(lines))
+			var actual []string
+
+			// EXERCISE
+			g.MaximalCliques(func(clique []string) {
+				actual = append(actual, strings.Join(clique, ","))
+			})
+
+			// VERIFY
+			slices.Sort(actual)
+			req.Equal(expected, actual)
+		})
+	}
+
+	run("empty", nil, []string{""})
+	run("edge", []string{"a-b"}, []string{"a,b"})
+	run("triangle and tail", []string{"a-b", "b-c", "c-a", "c-d"}, []string{"a,b,c", "c,d"})
+	run(
+		"two squares",
+		[]string{"a-b", "b-c", "c-d", "d-a", "a-c", "b-d", "d-e", "e-f", "f-d"},
+		[]string{"a,b,c,d", "d,e,f"},
+	)
+	run("disconnected", []string{"a-b", "c-d"}, []string{"a,b", "c,d"})
+}
+
+func TestMaximumClique(t *testing.T) {
+	req := require.New(t)
+	g := NewUndirected(ParseEdges([]string{"d-e", "e-f", "f-d", "a-b", "b-c", "c-a"}))
+	req.Equal([]string{"a", "b", "c"}, g.MaximumClique())
+	req.Nil(NewUndirected(nil).MaximumClique())
+}
+
+func TestTriangles(t *testing.T) {
+	req := require.New(t)
+	g := NewUndirected(ParseEdges([]string{"a-b", "b-c", "c-a", "c-d", "d-a", "x-x"}))
+	req.Equal([][]string{{"a", "b", "c"}, {"a", "c", "d"}}, g.Triangles())
+	req.True(g.Connected("a", "d"))
+	req.False(g.Connected("b", "d"))
+	req.False(g.Connected("b", "nope"))
+	req.Equal([]string{"a", "b", "c", "d", "x"}, g.Names())
+}
+
+func parseDirected(lines ...string) *Directed {
+	return NewDirected(ParseEdges(lines))
+}
+
+func TestTopologicalOrder(t *testing.T) {
+	run := func(name string, g *Directed, from string, expected []string, expectedErr string) {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+
+			// EXERCISE
+			actual, err := g.TopologicalOrder(from)
+
+			// VERIFY
+			if expectedErr != "" {
+				req.EqualError(err, expectedErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(expected, actual)
+		})
+	}
+
+	run("single", parseDirected("a-b"), "b", []string{"b"}, "")
+	run("chain", parseDirected("c-b", "b-a"), "c", []string{"c", "b", "a"}, "")
+	run(
+		"diamond",
+		parseDirected("a-c", "a-b", "b-d", "c-d", "x-a"),
+		"a",
+		[]string{"a", "b", "c", "d"},
+		"",
+	)
+	run("unknown", parseDirected("a-b"), "x", nil, "no such node: x")
+	run("cycle", parseDirected("s-a", "a-b", "b-c", "c-a"), "s", nil, "cycle found: a -> b -> c -> a")
+	run("self", parseDirected("s-a", "a-a"), "s", nil, "cycle found: a -> a")
+	run("unreachable cycle", parseDirected("s-a", "b-c", "c-b"), "s", []string{"s", "a"}, "")
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	run := func(name string, g *Directed, expected [][]string) {
+		t.Run(name, func(t *testing.T) {
+			// EXERCISE & VERIFY
+			require.Equal(t, expected, g.StronglyConnectedComponents())
+		})
+	}
+
+	run("chain", parseDirected("a-b", "b-c"), [][]string{{"a"}, {"b"}, {"c"}})
+	run("cycle", parseDirected("c-a", "a-b", "b-c"), [][]string{{"a", "b", "c"}})
+	run(
+		"two cycles",
+		parseDirected("s-a", "a-b", "b-a", "s-x", "x-y", "y-z", "z-x", "b-out", "z-out"),
+		[][]string{{"a", "b"}, {"out"}, {"s"}, {"x", "y", "z"}},
+	)
+	run("self", parseDirected("a-a", "a-b"), [][]string{{"a"}, {"b"}})
+}
+
+func TestCountPaths(t *testing.T) {
+	run := func(
+		name string,
+		g *Directed,
+		from, to string,
+		waypoints []string,
+		expected int64,
+		expectedErr string,
+	) {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+
+			// EXERCISE
+			actual, err := g.CountPaths(from, to, waypoints...)
+
+			// VERIFY
+			if expectedErr != "" {
+				req.EqualError(err, expectedErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(expected, actual.Int64())
+		})
+	}
+
+	diamond := parseDirected("a-b", "a-c", "b-d", "c-d", "d-e", "d-f", "e-g", "f-g")
+	run("same", diamond, "a", "a", nil, 1, "")
+	run("diamond", diamond, "a", "d", nil, 2, "")
+	run("double diamond", diamond, "a", "g", nil, 4, "")
+	run("waypoint", diamond, "a", "g", []string{"b"}, 2, "")
+	run("waypoints", diamond, "a", "g", []string{"f", "b"}, 1, "")
+	run("incomparable waypoints", diamond, "a", "g", []string{"b", "c"}, 0, "")
+	run("backwards", diamond, "g", "a", nil, 0, "")
+	run("unknown waypoint", diamond, "a", "g", []string{"x"}, 0, "no such node: x")
+	run("unknown target", diamond, "a", "x", nil, 0, "no such node: x")
+	run("cycle", parseDirected("a-b", "b-a"), "a", "b", nil, 0, "cycle found: a -> b -> a")
+	{
+		// 2^70 paths through 70 diamonds.
+		var lines []string
+		for i := range 70 {
+			lines = append(
+				lines,
+				fmt.Sprintf("n%d-l%d", i, i),
+				fmt.Sprintf("n%d-r%d", i, i),
+				fmt.Sprintf("l%d-n%d", i, i+1),
+				fmt.Sprintf("r%d-n%d", i, i+1),
+			)
+		}
+		actual, err := parseDirected(lines...).CountPaths("n0", "n70")
+		require.NoError(t, err)
+		require.Equal(t, new(big.Int).Lsh(big.NewInt(1), 70), actual)
+	}
+}
+
+func createDiagram() *Diagram {
+	diagram := NewDiagram(true)
+	diagram.AddNode("a", "start")
+	diagram.AddNode("b", "")
+	diagram.AddEdge("a", "b", "3")
+	diagram.AddEdge("b", "c", "")
+	return diagram
+}
+
+func TestDiagramDOT(t *testing.T) {
+	req := require.New(t)
+	diagram := createDiagram()
+	req.NoError(diagram.HighlightPath("a", "b"))
+	req.NoError(diagram.HighlightCut(shared.NewPair("b", "c")))
+
+	// EXERCISE & VERIFY
+	req.Equal(
+		strings.Join([]string{
+			"digraph {",
+			`    "a" [label="a (start)", shape=ellipse, color=red, penwidth=2];`,
+			`    "b" [label="b", shape=box, color=red, penwidth=2];`,
+			`    "c" [label="c", shape=box];`,
+			`    "a" -> "b" [label="3", color=red, penwidth=2];`,
+			`    "b" -> "c" [color=blue, style=dashed];`,
+			"}",
+			"",
+		}, "\n"),
+		diagram.DOT())
+}
+
+func TestDiagramMermaid(t *testing.T) {
+	req := require.New(t)
+	diagram := createDiagram()
+	req.NoError(diagram.HighlightPath("a"))
+	req.NoError(diagram.HighlightCut(shared.NewPair("b", "c")))
+
+	// EXERCISE & VERIFY
+	req.Equal(
+		strings.Join([]string{
+			"flowchart LR",
+			`    n0(["a (start)"])`,
+			`    n1["b"]`,
+			`    n2["c"]`,
+			`    n0 -->|"3"| n1`,
+			`    n1 --> n2`,
+			"    linkStyle 1 stroke:#4070f0,stroke-dasharray:5",
+			"    classDef highlight stroke:#e03030,stroke-width:3px",
+			"    class n0 highlight",
+			"",
+		}, "\n"),
+		diagram.Mermaid())
+}
+
+func TestDiagramHighlightErrors(t *testing.T) {
+	req := require.New(t)
+	diagram := createDiagram()
+	req.EqualError(diagram.HighlightPath("a", "x"), "unknown node: x")
+	req.EqualError(diagram.HighlightPath("b", "a"), "no edge from b to a")
+	req.EqualError(diagram.HighlightCut(shared.NewPair("a", "c")), "no edge from a to c")
+
+	undirected := NewDiagram(false)
+	undirected.AddEdge("a", "b", "")
+	req.NoError(undirected.HighlightPath("b", "a"))
+}
@@ -0,0 +1,236 @@
+// Package render draws boards for visual debugging: colourised terminal text, PNG images and
+// animated GIFs.
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/denarced/advent-of-code/shared"
+)
+
+var (
+	Black  = color.RGBA{R: 0x10, G: 0x10, B: 0x10, A: 0xff}
+	Grey   = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+	White  = color.RGBA{R: 0xf0, G: 0xf0, B: 0xf0, A: 0xff}
+	Red    = color.RGBA{R: 0xe0, G: 0x30, B: 0x30, A: 0xff}
+	Green  = color.RGBA{R: 0x30, G: 0xc0, B: 0x40, A: 0xff}
+	Yellow = color.RGBA{R: 0xf0, G: 0xd0, B: 0x30, A: 0xff}
+	Blue   = color.RGBA{R: 0x40, G: 0x70, B: 0xf0, A: 0xff}
+)
+
+// Cell is what's drawn at a location. Char 0 keeps the character below it.
+type Cell struct {
+	Char  rune
+	Color color.RGBA
+}
+
+// Layer is drawn on top of the board and the layers before it.
+type Layer map[shared.Loc]Cell
+
+// Highlight colours the cells without changing their characters.
+func Highlight(locs []shared.Loc, c color.RGBA) Layer {
+	return Mark(locs, 0, c)
+}
+
+// Mark replaces the characters of the cells.
+func Mark(locs []shared.Loc, char rune, c color.RGBA) Layer {
+	layer := Layer{}
+	for _, each := range locs {
+		layer[each] = Cell{Char: char, Color: c}
+	}
+	return layer
+}
+
+// Path marks each cell of the route, except the first one, with the direction moved into it:
+// ">", "v", "<" or "^". For example, a cell reached by moving right gets ">".
+func Path(route []shared.Loc, c color.RGBA) Layer {
+	layer := Layer{}
+	for i := 1; i < len(route); i++ {
+		from, to := route[i-1], route[i]
+		char := '^'
+		switch {
+		case to.X > from.X:
+			char = '>'
+		case to.X < from.X:
+			char = '<'
+		case to.Y < from.Y:
+			char = 'v'
+		}
+		layer[to] = Cell{Char: char, Color: c}
+	}
+	return layer
+}
+
+// Label writes the text from the location to the right.
+func Label(loc shared.Loc, text string, c color.RGBA) Layer {
+	layer := Layer{}
+	for i, each := range []rune(text) {
+		layer[shared.Loc{X: loc.X + i, Y: loc.Y}] = Cell{Char: each, Color: c}
+	}
+	return layer
+}
+
+// Scene is a board with layers on top of it. Palette colours the board's characters, the rest are
+// grey.
+type Scene struct {
+	Board   *shared.Board
+	Layers  []Layer
+	Palette map[rune]color.RGBA
+}
+
+// NewScene creates a scene where "." is black and "#" white.
+func NewScene(brd *shared.Board, layers ...Layer) *Scene {
+	return &Scene{
+		Board:   brd,
+		Layers:  layers,
+		Palette: map[rune]color.RGBA{'.': Black, ' ': Black, '#': White},
+	}
+}
+
+// Blank creates a board of "." for things that don't have one.
+func Blank(width, height int) *shared.Board {
+	lines := make([]string, height)
+	for i := range lines {
+		lines[i] = strings.Repeat(".", width)
+	}
+	return shared.NewBoard(lines)
+}
+
+// Cells returns the cells of the scene top row first, as the board is printed.
+func (v *Scene) Cells() [][]Cell {
+	height := v.Board.GetHeight()
+	rows := make([][]Cell, height)
+	for r := range height {
+		y := height - 1 - r
+		for x := 0; ; x++ {
+			loc := shared.Loc{X: x, Y: y}
+			char, ok := v.Board.Get(loc)
+			if !ok {
+				break
+			}
+			rows[r] = append(rows[r], v.cellAt(loc, char))
+		}
+	}
+	return rows
+}
+
+func (v *Scene) cellAt(loc shared.Loc, char rune) Cell {
+	cell := Cell{Char: char, Color: Grey}
+	if c, ok := v.Palette[char]; ok {
+		cell.Color = c
+	}
+	for _, each := range v.Layers {
+		if top, ok := each[loc]; ok {
+			if top.Char != 0 {
+				cell.Char = top.Char
+			}
+			cell.Color = top.Color
+		}
+	}
+	return cell
+}
+
+// Lines renders the scene as plain text.
+func Lines(scene *Scene) []string {
+	var lines []string
+	for _, row := range scene.Cells() {
+		var b strings.Builder
+		for _, each := range row {
+			b.WriteRune(each.Char)
+		}
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+// ANSI writes the scene as text coloured with 24-bit terminal escape codes.
+func ANSI(w io.Writer, scene *Scene) error {
+	var b strings.Builder
+	for _, row := range scene.Cells() {
+		var previous *color.RGBA
+		for _, each := range row {
+			if previous == nil || *previous != each.Color {
+				fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm", each.Color.R, each.Color.G, each.Color.B)
+				previous = &each.Color
+			}
+			b.WriteRune(each.Char)
+		}
+		b.WriteString("\x1b[0m\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Image draws each cell as a square of scale pixels in the cell's colour.
+func Image(scene *Scene, scale int) *image.RGBA {
+	rows := scene.Cells()
+	width := 0
+	for _, each := range rows {
+		width = max(width, len(each))
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width*scale, len(rows)*scale))
+	draw.Draw(img, img.Bounds(), image.NewUniform(Black), image.Point{}, draw.Src)
+	for r, row := range rows {
+		for c, each := range row {
+			rect := image.Rect(c*scale, r*scale, (c+1)*scale, (r+1)*scale)
+			draw.Draw(img, rect, image.NewUniform(each.Color), image.Point{}, draw.Src)
+		}
+	}
+	return img
+}
+
+// PNG writes the scene as a PNG image. See Image.
+func PNG(w io.Writer, scene *Scene, scale int) error {
+	return png.Encode(w, Image(scene, scale))
+}
+
+// GIF writes the scenes as frames of an animated GIF. Delay is the time between frames in 100ths of
+// a second.
+func GIF(w io.Writer, scenes []*Scene, scale, delay int) error {
+	if len(scenes) == 0 {
+		return fmt.Errorf("no frames")
+	}
+	images := make([]*image.RGBA, 0, len(scenes))
+	for _, each := range scenes {
+		images = append(images, Image(each, scale))
+	}
+	pal := derivePalette(images)
+	// Frames can differ in size, e.g. when a replay has several simulations.
+	anim := &gif.GIF{Config: image.Config{ColorModel: pal}}
+	for _, each := range images {
+		anim.Config.Width = max(anim.Config.Width, each.Bounds().Dx())
+		anim.Config.Height = max(anim.Config.Height, each.Bounds().Dy())
+		frame := image.NewPaletted(each.Bounds(), pal)
+		draw.Draw(frame, frame.Bounds(), each, image.Point{}, draw.Src)
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// derivePalette collects the colours of the images. GIF allows 256 colours so the rest are drawn
+// with the nearest one.
+func derivePalette(images []*image.RGBA) color.Palette {
+	seen := map[color.RGBA]bool{}
+	var pal color.Palette
+	for _, img := range images {
+		bounds := img.Bounds()
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X && len(pal) < 256; x++ {
+				c := img.RGBAAt(x, y)
+				if !seen[c] {
+					seen[c] = true
+					pal = append(pal, c)
+				}
+			}
+		}
+	}
+	return pal
+}
@@ -0,0 +1,117 @@
+package render
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/stretchr/testify/require"
+)
+
+func createScene() *Scene {
+	brd := shared.NewBoard([]string{
+		"#..",
+		"...",
+		"..#",
+	})
+	return NewScene(
+		brd,
+		Path([]shared.Loc{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 0}}, Red),
+		Highlight([]shared.Loc{{X: 2, Y: 0}}, Green),
+		Label(shared.Loc{X: 1, Y: 2}, "ab", Blue),
+	)
+}
+
+func TestLines(t *testing.T) {
+	shared.InitTestLogging(t)
+	require.Equal(t, []string{"#ab", ".>.", ".v#"}, Lines(createScene()))
+}
+
+func TestCells(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+
+	// EXERCISE
+	cells := createScene().Cells()
+
+	// VERIFY
+	req.Equal(Cell{Char: '#', Color: White}, cells[0][0])
+	req.Equal(Cell{Char: 'a', Color: Blue}, cells[0][1])
+	req.Equal(Cell{Char: '.', Color: Black}, cells[1][0])
+	req.Equal(Cell{Char: '>', Color: Red}, cells[1][1])
+	req.Equal(Cell{Char: '#', Color: Green}, cells[2][2])
+}
+
+func TestANSI(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	var b bytes.Buffer
+
+	// EXERCISE
+	req.NoError(ANSI(&b, createScene()))
+
+	// VERIFY
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	req.Len(lines, 3)
+	req.Equal("\x1b[38;2;240;240;240m#\x1b[38;2;64;112;240mab\x1b[0m", lines[0])
+	req.Equal("\x1b[38;2;16;16;16m.\x1b[38;2;224;48;48mv\x1b[38;2;48;192;64m#\x1b[0m", lines[2])
+}
+
+func TestPNG(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	var b bytes.Buffer
+
+	// EXERCISE
+	req.NoError(PNG(&b, createScene(), 4))
+
+	// VERIFY
+	img, err := png.Decode(&b)
+	req.NoError(err)
+	req.Equal(12, img.Bounds().Dx())
+	req.Equal(12, img.Bounds().Dy())
+	req.Equal(Red, color.RGBAModel.Convert(img.At(5, 5)))
+}
+
+func TestGIF(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	var b bytes.Buffer
+	second := createScene()
+	second.Layers = append(second.Layers, Mark([]shared.Loc{{X: 0, Y: 0}}, '@', Yellow))
+
+	// EXERCISE
+	req.NoError(GIF(&b, []*Scene{createScene(), second}, 2, 10))
+
+	// VERIFY
+	anim, err := gif.DecodeAll(&b)
+	req.NoError(err)
+	req.Len(anim.Image, 2)
+	req.Equal([]int{10, 10}, anim.Delay)
+	req.Equal(Yellow, color.RGBAModel.Convert(anim.Image[1].At(0, 5)))
+	req.Equal(Black, color.RGBAModel.Convert(anim.Image[0].At(0, 5)))
+}
+
+func TestGIFWithDifferentSizes(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	var b bytes.Buffer
+	small := NewScene(Blank(2, 1))
+
+	// EXERCISE
+	req.NoError(GIF(&b, []*Scene{small, createScene()}, 1, 10))
+
+	// VERIFY
+	anim, err := gif.DecodeAll(&b)
+	req.NoError(err)
+	req.Equal(3, anim.Config.Width)
+	req.Equal(3, anim.Config.Height)
+}
+
+func TestGIFWithoutFrames(t *testing.T) {
+	require.EqualError(t, GIF(&bytes.Buffer{}, nil, 1, 1), "no frames")
+}
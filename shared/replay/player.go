@@ -0,0 +1,83 @@
+package replay
+
+import (
+	"fmt"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/term"
+)
+
+const (
+	minFps = 1
+	maxFps = 240
+)
+
+// Player keeps track of playback: the frame, speed and whether it's paused.
+type Player struct {
+	Index  int
+	Fps    int
+	Paused bool
+	length int
+}
+
+// NewPlayer creates a player at the first frame.
+func NewPlayer(length, fps int) *Player {
+	return &Player{Fps: fps, length: length}
+}
+
+// Handle handles a key press. Space pauses, arrows step one frame when paused and ten when not,
+// "[" and "]" seek a tenth of the replay, "+" and "-" change speed, "0" rewinds and "q" quits.
+func (v *Player) Handle(key term.Key) (quit bool) {
+	step := shared.Or(v.Paused, 1, 10)
+	switch key {
+	case ' ':
+		v.Paused = !v.Paused
+	case term.KeyRight:
+		v.seek(step)
+	case term.KeyLeft:
+		v.seek(-step)
+	case ']':
+		v.seek(max(1, v.length/10))
+	case '[':
+		v.seek(-max(1, v.length/10))
+	case '+':
+		v.Fps = min(maxFps, v.Fps*2)
+	case '-':
+		v.Fps = max(minFps, v.Fps/2)
+	case '0':
+		v.Index = 0
+	case 'q', term.KeyEscape:
+		return true
+	}
+	return false
+}
+
+func (v *Player) seek(delta int) {
+	v.Index = max(0, min(v.length-1, v.Index+delta))
+}
+
+// Tick advances playback by a frame. It pauses at the last frame.
+func (v *Player) Tick() {
+	if v.Paused {
+		return
+	}
+	if v.Index >= v.length-1 {
+		v.Paused = true
+		return
+	}
+	v.Index++
+}
+
+// Status describes the playback state for a status line.
+func (v *Player) Status() string {
+	state := "playing"
+	if v.Paused {
+		state = "paused"
+	}
+	return fmt.Sprintf(
+		"frame %d/%d  %d fps  %s  [space] pause [<- ->] step [[ ]] seek [+ -] speed [q] quit",
+		v.Index+1,
+		v.length,
+		v.Fps,
+		state)
+}
@@ -0,0 +1,228 @@
+// Package replay records simulations frame by frame to a replay file and plays them back.
+package replay
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/denarced/advent-of-code/shared"
+)
+
+const (
+	version = 1
+	// Every keyframe has the whole board, others only the changed cells. Keyframes make seeking
+	// fast.
+	keyframeInterval = 100
+)
+
+type header struct {
+	Version int
+}
+
+type change struct {
+	X    int
+	Y    int
+	Char rune
+}
+
+type frame struct {
+	// Lines is set for keyframes.
+	Lines   []string
+	Changes []change
+}
+
+// Recorder writes boards to a gzipped stream of frames. Write errors are returned by Close.
+type Recorder struct {
+	w        *gzip.Writer
+	enc      *gob.Encoder
+	closer   io.Closer
+	previous []string
+	count    int
+	err      error
+}
+
+// NewRecorder creates a recorder that writes to w.
+func NewRecorder(w io.Writer) *Recorder {
+	zw := gzip.NewWriter(w)
+	rec := &Recorder{w: zw, enc: gob.NewEncoder(zw)}
+	rec.err = rec.enc.Encode(header{Version: version})
+	return rec
+}
+
+// Create creates a recorder that writes to a file.
+func Create(filep string) (*Recorder, error) {
+	f, err := os.Create(filep)
+	if err != nil {
+		return nil, err
+	}
+	rec := NewRecorder(f)
+	rec.closer = f
+	return rec, nil
+}
+
+// SetupRecording records the frames of cb to a file when environment variable aoc_record is set.
+// The returned callback finishes the recording.
+func SetupRecording(filen string, cb *func(brd *shared.Board)) (callback func()) {
+	callback = func() {}
+	if _, ok := os.LookupEnv("aoc_record"); !ok {
+		return
+	}
+	rec, err := Create(filen)
+	if err != nil {
+		shared.Logger.Error("Failed to setup recording.", "err", err)
+		return
+	}
+	*cb = rec.Record
+	return func() {
+		*cb = nil
+		if err := rec.Close(); err != nil {
+			shared.Logger.Error("Failed to finish recording.", "err", err)
+			return
+		}
+		shared.Logger.Info("Recording written.", "file", filen, "frames", rec.Count())
+	}
+}
+
+// Record records the board as the next frame.
+func (v *Recorder) Record(brd *shared.Board) {
+	if v.err != nil {
+		return
+	}
+	lines := brd.GetLines()
+	var f frame
+	if v.count%keyframeInterval == 0 || !isSameSize(v.previous, lines) {
+		f.Lines = lines
+	} else {
+		f.Changes = diff(v.previous, lines)
+	}
+	v.err = v.enc.Encode(f)
+	v.previous = lines
+	v.count++
+}
+
+func isSameSize(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func diff(previous, lines []string) []change {
+	var changes []change
+	for r := range lines {
+		if previous[r] == lines[r] {
+			continue
+		}
+		prev := []rune(previous[r])
+		for c, each := range []rune(lines[r]) {
+			if prev[c] != each {
+				changes = append(changes, change{X: c, Y: len(lines) - 1 - r, Char: each})
+			}
+		}
+	}
+	return changes
+}
+
+// Count is the number of recorded frames.
+func (v *Recorder) Count() int {
+	return v.count
+}
+
+// Close finishes the stream and closes the file if the recorder created one.
+func (v *Recorder) Close() error {
+	err := errors.Join(v.err, v.w.Close())
+	if v.closer != nil {
+		err = errors.Join(err, v.closer.Close())
+	}
+	return err
+}
+
+// Replay is a recorded simulation.
+type Replay struct {
+	frames  []frame
+	current *shared.Board
+	index   int
+}
+
+// Read reads a replay stream.
+func Read(r io.Reader) (*Replay, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	dec := gob.NewDecoder(zr)
+	var h header
+	if err := dec.Decode(&h); err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	if h.Version != version {
+		return nil, fmt.Errorf("unsupported replay version: %d", h.Version)
+	}
+	rep := &Replay{index: -1}
+	for {
+		var f frame
+		err := dec.Decode(&f)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read frame %d: %w", len(rep.frames), err)
+		}
+		if len(rep.frames) == 0 && f.Lines == nil {
+			return nil, fmt.Errorf("first frame isn't a keyframe")
+		}
+		rep.frames = append(rep.frames, f)
+	}
+	return rep, nil
+}
+
+// Open reads a replay file.
+func Open(filep string) (*Replay, error) {
+	f, err := os.Open(filep)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Len is the number of frames.
+func (v *Replay) Len() int {
+	return len(v.frames)
+}
+
+// Board returns the board of the frame. The board is reused for the next frame so copy it to keep
+// it.
+func (v *Replay) Board(index int) *shared.Board {
+	if index < 0 || index >= len(v.frames) {
+		panic(fmt.Sprintf("Frame out of range: %d.", index))
+	}
+	if index < v.index || v.current == nil {
+		v.index = -1
+	}
+	// Jump to the latest keyframe unless it's behind the current frame.
+	key := index
+	for v.frames[key].Lines == nil {
+		key--
+	}
+	if key > v.index {
+		v.current = shared.NewBoard(v.frames[key].Lines)
+		v.index = key
+	}
+	for v.index < index {
+		v.index++
+		for _, each := range v.frames[v.index].Changes {
+			v.current.Set(shared.Loc{X: each.X, Y: each.Y}, each.Char)
+		}
+	}
+	return v.current
+}
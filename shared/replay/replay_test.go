@@ -0,0 +1,105 @@
+package replay
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/denarced/advent-of-code/shared"
+	"github.com/denarced/advent-of-code/shared/term"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndRead(t *testing.T) {
+	shared.InitTestLogging(t)
+	req := require.New(t)
+	var b bytes.Buffer
+	rec := NewRecorder(&b)
+	brd := shared.NewBoard([]string{
+		"@..",
+		"...",
+	})
+	var expected [][]string
+	record := func() {
+		rec.Record(brd)
+		expected = append(expected, brd.GetLines())
+	}
+	record()
+	// Enough frames to have more than one keyframe.
+	for i := range 2*keyframeInterval + 5 {
+		brd.Set(shared.Loc{X: i % 3, Y: i % 2}, rune('a'+i%26))
+		record()
+	}
+	// Resizing creates a keyframe.
+	brd = shared.NewBoard([]string{"##", "#."})
+	record()
+	req.NoError(rec.Close())
+	req.Equal(len(expected), rec.Count())
+
+	// EXERCISE
+	rep, err := Read(&b)
+
+	// VERIFY
+	req.NoError(err)
+	req.Equal(len(expected), rep.Len())
+	for _, index := range []int{0, 1, 2, 150, 99, 100, 101, 205, 206, 3} {
+		req.Equal(expected[index], rep.Board(index).GetLines(), "frame %d", index)
+	}
+	for i := range expected {
+		req.Equal(expected[i], rep.Board(i).GetLines(), "frame %d", i)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	_, err := Read(bytes.NewBufferString("not gzip"))
+	require.Error(t, err)
+}
+
+func TestPlayer(t *testing.T) {
+	run := func(name string, keys []term.Key, expected Player, expectedQuit bool) {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			player := NewPlayer(50, 10)
+			player.Index = 20
+			quit := false
+
+			// EXERCISE
+			for _, each := range keys {
+				quit = player.Handle(each)
+			}
+
+			// VERIFY
+			req.Equal(expected, *player)
+			req.Equal(expectedQuit, quit)
+		})
+	}
+
+	run("pause", []term.Key{' '}, Player{Index: 20, Fps: 10, Paused: true, length: 50}, false)
+	run("skip", []term.Key{term.KeyRight}, Player{Index: 30, Fps: 10, length: 50}, false)
+	run(
+		"step",
+		[]term.Key{' ', term.KeyLeft},
+		Player{Index: 19, Fps: 10, Paused: true, length: 50},
+		false)
+	run(
+		"seek to the end",
+		[]term.Key{']', ']', ']', ']', ']', ']'},
+		Player{Index: 49, Fps: 10, length: 50},
+		false)
+	run("seek to the start", []term.Key{'[', '[', '[', '[', '['}, Player{Fps: 10, length: 50}, false)
+	run("faster", []term.Key{'+', '+'}, Player{Index: 20, Fps: 40, length: 50}, false)
+	run("slower", []term.Key{'-', '-', '-', '-'}, Player{Index: 20, Fps: 1, length: 50}, false)
+	run("rewind", []term.Key{'0'}, Player{Fps: 10, length: 50}, false)
+	run("quit", []term.Key{'q'}, Player{Index: 20, Fps: 10, length: 50}, true)
+}
+
+func TestPlayerTick(t *testing.T) {
+	req := require.New(t)
+	player := NewPlayer(3, 10)
+	var indexes []string
+	for range 4 {
+		player.Tick()
+		indexes = append(indexes, fmt.Sprintf("%d %t", player.Index, player.Paused))
+	}
+	req.Equal([]string{"1 false", "2 false", "2 true", "2 true"}, indexes)
+}
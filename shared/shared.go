@@ -3,6 +3,7 @@ package shared
 import (
 	"fmt"
 	"math"
+	"math/bits"
 	"os"
 	"runtime/pprof"
 	"slices"
@@ -593,7 +594,13 @@ func ModForIndex(index, length int) int {
 	if length == 0 {
 		return 0
 	}
-	return ((index % length) + length) % length
+	// Adding length only when the signs differ keeps lengths near the maximum int from
+	// overflowing.
+	mod := index % length
+	if mod != 0 && (mod < 0) != (length < 0) {
+		mod += length
+	}
+	return mod
 }
 
 func DeriveGreatestCommonDivisor(a, b int) int {
@@ -614,3 +621,42 @@ func DeriveLeastCommonMultiple(a, b int, rest ...int) int {
 	}
 	return common
 }
+
+// Congruence is x ≡ Remainder (mod Modulus).
+type Congruence struct {
+	Remainder int
+	Modulus   int
+}
+
+// CombineCongruences combines two congruences into one with the generalised Chinese remainder
+// theorem. The moduli don't need to be coprime. If there's no x that satisfies both, ok is false.
+func CombineCongruences(a, b Congruence) (combined Congruence, ok bool) {
+	gcd, inverse, _ := extendedGcd(a.Modulus, b.Modulus)
+	diff := b.Remainder - a.Remainder
+	if diff%gcd != 0 {
+		return
+	}
+	reduced := b.Modulus / gcd
+	// a.Remainder + a.Modulus*k ≡ b.Remainder (mod b.Modulus), solved for k.
+	k := mulMod(ModForIndex(diff/gcd, reduced), ModForIndex(inverse, reduced), reduced)
+	modulus := a.Modulus * reduced
+	return Congruence{
+		Remainder: ModForIndex(a.Remainder+a.Modulus*k, modulus),
+		Modulus:   modulus,
+	}, true
+}
+
+// mulMod multiplies non-negative a and b modulo m without overflowing.
+func mulMod(a, b, m int) int {
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	return int(bits.Rem64(hi, lo, uint64(m)))
+}
+
+// extendedGcd returns gcd(a, b) and x and y so that a*x + b*y = gcd(a, b).
+func extendedGcd(a, b int) (gcd, x, y int) {
+	if b == 0 {
+		return a, 1, 0
+	}
+	gcd, x1, y1 := extendedGcd(b, a%b)
+	return gcd, y1, x1 - (a/b)*y1
+}
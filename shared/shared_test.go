@@ -2,6 +2,7 @@ package shared
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"testing"
 
@@ -79,6 +80,9 @@ func TestModForIndex(t *testing.T) {
 	run(2, 2, 0)
 	run(3, 2, 1)
 	run(4, 2, 0)
+
+	run(-1, math.MaxInt, math.MaxInt-1)
+	run(math.MaxInt-1, math.MaxInt, math.MaxInt-1)
 }
 
 func TestDeriveGreeatestCommonDivisor(t *testing.T) {
@@ -108,3 +112,68 @@ func TestDeriveLeastCommonMultiple(t *testing.T) {
 		})
 	}
 }
+
+func TestTrieCountSegmentations(t *testing.T) {
+	trie := NewTrie("r", "wr", "b", "g", "bwu", "rb", "gb", "br")
+	run := func(s string, expected int64) {
+		t.Run(s, func(t *testing.T) {
+			req := require.New(t)
+
+			// EXERCISE & VERIFY
+			req.Equal(expected, trie.CountSegmentations(s).Int64())
+		})
+	}
+
+	run("", 1)
+	run("brwrr", 2)
+	run("bggr", 1)
+	run("gbbr", 4)
+	run("rrbgbr", 6)
+	run("ubwu", 0)
+	run("bwurrg", 1)
+	run("brgr", 2)
+	run("bbrgwb", 0)
+}
+
+func TestTriePrefixes(t *testing.T) {
+	req := require.New(t)
+	trie := NewTrie("a", "ab", "abc", "b")
+	req.Equal([]int{1, 2, 3}, trie.Prefixes("abcd", 0))
+	req.Equal([]int{1}, trie.Prefixes("abcd", 1))
+	req.Nil(trie.Prefixes("abcd", 2))
+}
+
+func TestCombineCongruences(t *testing.T) {
+	run := func(a, b Congruence, expected Congruence, expectedOk bool) {
+		name := fmt.Sprintf("%v and %v", a, b)
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+
+			// EXERCISE
+			actual, ok := CombineCongruences(a, b)
+
+			// VERIFY
+			req.Equal(expectedOk, ok)
+			req.Equal(expected, actual)
+		})
+	}
+
+	run(Congruence{2, 3}, Congruence{3, 5}, Congruence{8, 15}, true)
+	run(Congruence{3, 5}, Congruence{2, 3}, Congruence{8, 15}, true)
+	run(Congruence{0, 1}, Congruence{4, 7}, Congruence{4, 7}, true)
+	// Not coprime.
+	run(Congruence{2, 4}, Congruence{4, 6}, Congruence{10, 12}, true)
+	run(Congruence{1, 4}, Congruence{2, 6}, Congruence{}, false)
+	run(Congruence{5, 6}, Congruence{5, 6}, Congruence{5, 6}, true)
+	// Moduli near 2^31 and 2^32 whose products of remainders overflow int.
+	run(
+		Congruence{2147483646, 2147483647},
+		Congruence{4294967290, 4294967291},
+		Congruence{9223372021822390276, 9223372021822390277},
+		true)
+	run(
+		Congruence{123456789, 2147483647},
+		Congruence{4000000000, 4294967291},
+		Congruence{2475418094523573708, 9223372021822390277},
+		true)
+}
@@ -0,0 +1,95 @@
+// Package term reads keys from the terminal for the interactive commands.
+package term
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Key is a key press: either the typed character or one of the special keys below.
+type Key rune
+
+const (
+	KeyUp Key = -1 - iota
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyEscape
+)
+
+// ClearScreen moves the cursor to the top left corner and clears the terminal.
+const ClearScreen = "\x1b[H\x1b[2J"
+
+// MakeRaw switches the terminal to read keys one at a time without echoing them. Signals such as
+// ctrl-c still work. Restore switches back to the previous mode.
+func MakeRaw() (restore func(), err error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, err
+	}
+	return func() {
+		_, _ = stty(strings.TrimSpace(state))
+	}, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// ReadKey reads the next key. Arrow keys are escape sequences such as "\x1b[A".
+func ReadKey(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if b != 0x1b {
+		return Key(b), nil
+	}
+	// A lone escape isn't followed by anything that was typed at the same time.
+	if r.Buffered() < 2 {
+		return KeyEscape, nil
+	}
+	if next, _ := r.Peek(1); next[0] != '[' {
+		return KeyEscape, nil
+	}
+	_, _ = r.ReadByte()
+	b, err = r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	switch b {
+	case 'A':
+		return KeyUp, nil
+	case 'B':
+		return KeyDown, nil
+	case 'C':
+		return KeyRight, nil
+	case 'D':
+		return KeyLeft, nil
+	}
+	return KeyEscape, nil
+}
+
+// ReadKeys reads keys until the reader fails and sends them to the returned channel.
+func ReadKeys(r *bufio.Reader) <-chan Key {
+	ch := make(chan Key)
+	go func() {
+		defer close(ch)
+		for {
+			key, err := ReadKey(r)
+			if err != nil {
+				return
+			}
+			ch <- key
+		}
+	}()
+	return ch
+}
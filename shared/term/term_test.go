@@ -0,0 +1,25 @@
+package term
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadKey(t *testing.T) {
+	req := require.New(t)
+	r := bufio.NewReader(strings.NewReader("a \x1b[A\x1b[B\x1b[C\x1b[Dq\x1b"))
+	var keys []Key
+	for {
+		key, err := ReadKey(r)
+		if err == io.EOF {
+			break
+		}
+		req.NoError(err)
+		keys = append(keys, key)
+	}
+	req.Equal([]Key{'a', ' ', KeyUp, KeyDown, KeyRight, KeyLeft, 'q', KeyEscape}, keys)
+}
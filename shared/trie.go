@@ -0,0 +1,61 @@
+package shared
+
+import "math/big"
+
+// Trie is a prefix tree of words.
+type Trie struct {
+	kids map[byte]*Trie
+	end  bool
+}
+
+func NewTrie(words ...string) *Trie {
+	t := &Trie{kids: map[byte]*Trie{}}
+	for _, each := range words {
+		t.Add(each)
+	}
+	return t
+}
+
+func (v *Trie) Add(word string) {
+	node := v
+	for i := range len(word) {
+		kid := node.kids[word[i]]
+		if kid == nil {
+			kid = &Trie{kids: map[byte]*Trie{}}
+			node.kids[word[i]] = kid
+		}
+		node = kid
+	}
+	node.end = true
+}
+
+// Prefixes returns the lengths of the words that s[start:] starts with.
+func (v *Trie) Prefixes(s string, start int) []int {
+	var lengths []int
+	node := v
+	for i := start; i < len(s); i++ {
+		node = node.kids[s[i]]
+		if node == nil {
+			break
+		}
+		if node.end {
+			lengths = append(lengths, i-start+1)
+		}
+	}
+	return lengths
+}
+
+// CountSegmentations counts the ways s can be split into words of the trie.
+func (v *Trie) CountSegmentations(s string) *big.Int {
+	// counts[i] is the number of ways to segment s[i:].
+	counts := make([]*big.Int, len(s)+1)
+	counts[len(s)] = big.NewInt(1)
+	for i := len(s) - 1; i >= 0; i-- {
+		count := new(big.Int)
+		for _, length := range v.Prefixes(s, i) {
+			count.Add(count, counts[i+length])
+		}
+		counts[i] = count
+	}
+	return counts[0]
+}